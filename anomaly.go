@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// minBaselineStdDev keeps a flat baseline from turning tiny changes into anomalies
+	minBaselineStdDev = 1.0
+
+	// baselineSaveInterval is how often updated baselines are written to the state file
+	baselineSaveInterval = 10 * time.Minute
+)
+
+// Baseline is an exponentially weighted moving mean and variance of a metric
+type Baseline struct {
+	Mean           float64   `json:"mean"`
+	Variance       float64   `json:"variance"`
+	Samples        int       `json:"samples"`
+	Updated        time.Time `json:"updated"`
+	AnomalousSince time.Time `json:"anomalous_since,omitempty"`
+}
+
+// StdDev returns the baseline standard deviation, never less than minBaselineStdDev
+func (b *Baseline) StdDev() float64 {
+	return math.Max(math.Sqrt(b.Variance), minBaselineStdDev)
+}
+
+// Deviation returns how many standard deviations value is from the baseline mean
+func (b *Baseline) Deviation(value float64) float64 {
+	return (value - b.Mean) / b.StdDev()
+}
+
+// Update folds a sample into the baseline. Older samples lose half their weight
+// every halfLife, so irregular check intervals are weighted by elapsed time.
+// Until enough samples are seen the baseline is a plain running average.
+func (b *Baseline) Update(value float64, at time.Time, halfLife time.Duration) {
+	if b.Samples == 0 {
+		b.Mean = value
+		b.Variance = 0
+		b.Samples = 1
+		b.Updated = at
+		return
+	}
+
+	elapsed := at.Sub(b.Updated)
+	if elapsed <= 0 {
+		elapsed = time.Second
+	}
+	alpha := 1 - math.Exp(-math.Ln2*elapsed.Seconds()/halfLife.Seconds())
+	alpha = math.Max(alpha, 1/float64(b.Samples+1))
+
+	diff := value - b.Mean
+	increment := alpha * diff
+	b.Mean += increment
+	b.Variance = (1 - alpha) * (b.Variance + diff*increment)
+	b.Samples++
+	b.Updated = at
+}
+
+// anomalyCheckKey returns the check name used for anomaly alerts of a metric
+func anomalyCheckKey(metricKey string) string {
+	return metricKey + "-anomaly"
+}
+
+// checkAnomaly compares a sample against the metric's baseline, alerts once the
+// deviation has lasted for the sustained period, and then learns from the sample.
+// The caller must hold m.mu.
+func (m *Monitor) checkAnomaly(metricKey string, config MonitoringConfig, usage float64, metricName string) {
+	anomaly := config.Anomaly
+	if !anomaly.Enabled {
+		return
+	}
+
+	baseline, ok := m.baselines[metricKey]
+	if !ok {
+		baseline = &Baseline{}
+		m.baselines[metricKey] = baseline
+	}
+
+	now := time.Now()
+	mean, stddev := baseline.Mean, baseline.StdDev()
+	deviation := 0.0
+	if baseline.Samples >= anomaly.MinSamples {
+		deviation = baseline.Deviation(usage)
+	}
+	baseline.Update(usage, now, time.Duration(anomaly.HalfLifeMinutes)*time.Minute)
+	// Baselines drift slowly, so saving them now and then is enough
+	if now.Sub(m.stateSavedAt) >= baselineSaveInterval {
+		m.stateChanged = true
+	}
+
+	if math.Abs(deviation) <= anomaly.Sigma {
+		baseline.AnomalousSince = time.Time{}
+	} else if baseline.AnomalousSince.IsZero() {
+		baseline.AnomalousSince = now
+	}
+
+	checkKey := anomalyCheckKey(metricKey)
+	sustained := time.Duration(anomaly.SustainedMinutes) * time.Minute
+	firing := !baseline.AnomalousSince.IsZero() && now.Sub(baseline.AnomalousSince) >= sustained
+	state := m.updateCheckState(checkKey, firing)
+	if !firing || state.Flapping {
+		return
+	}
+
+	if recentlySent(state, m.checkInterval(metricKey)) {
+		return
+	}
+
+	direction := "above"
+	if deviation < 0 {
+		direction = "below"
+	}
+
+	hostname, serverIP := GetServerInfo()
+	message := &NotificationMessage{
+		Type:  NotificationTypeSlack, // Will be overridden by providers
+		Level: NotificationLevelWarning,
+		Title: fmt.Sprintf("%s Usage Anomaly", metricName),
+		Message: fmt.Sprintf("%s usage is %.1f standard deviations %s its baseline of %.2f%% since %s",
+			metricName, math.Abs(deviation), direction, mean, baseline.AnomalousSince.Format("15:04")),
+		Hostname:  hostname,
+		IP:        serverIP,
+		Timestamp: now,
+		Metric:    fmt.Sprintf("%s Usage", metricName),
+		Value:     fmt.Sprintf("%.2f%%", usage),
+		Threshold: fmt.Sprintf("%.2f%% ± %.2f", mean, anomaly.Sigma*stddev),
+		AlertID:   state.AlertID,
+		Since:     state.Since,
+		Check:     checkKey,
+		Labels:    m.alertLabels(metricKey),
+	}
+
+	m.notify(message, config.EffectiveRateLimits(), nil)
+}
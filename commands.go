@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"syscall"
@@ -84,6 +85,23 @@ func NewLogsCmd() *cobra.Command {
 	}
 }
 
+// NewAckCmd creates the ack command
+func NewAckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ack <alert-id>",
+		Short: "Acknowledge an alert and stop its escalation",
+		Long: `Acknowledge an alert and stop its escalation.
+
+The daemon is reached through its control socket: control.socket from the
+configuration, or a per-user default. To acknowledge alerts of a daemon running
+as another user (e.g. root), set control.socket and control.group, or pass --socket.`,
+		Args: cobra.ExactArgs(1),
+		Run:  runAck,
+	}
+	cmd.Flags().String("socket", "", "path of the daemon control socket")
+	return cmd
+}
+
 // NewDaemonCmd creates the daemon command
 func NewDaemonCmd() *cobra.Command {
 	return &cobra.Command{
@@ -186,16 +204,39 @@ func showStatus(config *Config) {
 	// Show monitoring configuration
 	fmt.Println("\n🔍 Monitoring Configuration:")
 	if config.Disk.Enabled {
-		fmt.Printf("  • Disk usage (threshold: %d%%, check every %d hours, max alerts: %d/day)\n",
-			config.Disk.Threshold, config.Disk.CheckInterval, config.Disk.MaxDailyAlerts)
+		fmt.Printf("  • Disk usage (threshold: %d%%, check every %s, max alerts: %s)\n",
+			config.Disk.Threshold, config.Disk.CheckInterval, describeRateLimits(config.Disk.EffectiveRateLimits()))
 	}
 	if config.CPU.Enabled {
-		fmt.Printf("  • CPU usage (threshold: %d%%, check every %d minutes, max alerts: %d/day)\n",
-			config.CPU.Threshold, config.CPU.CheckInterval, config.CPU.MaxDailyAlerts)
+		fmt.Printf("  • CPU usage (threshold: %d%%, check every %s, max alerts: %s)\n",
+			config.CPU.Threshold, config.CPU.CheckInterval, describeRateLimits(config.CPU.EffectiveRateLimits()))
 	}
 	if config.Memory.Enabled {
-		fmt.Printf("  • Memory usage (threshold: %d%%, check every %d minutes, max alerts: %d/day)\n",
-			config.Memory.Threshold, config.Memory.CheckInterval, config.Memory.MaxDailyAlerts)
+		fmt.Printf("  • Memory usage (threshold: %d%%, check every %s, max alerts: %s)\n",
+			config.Memory.Threshold, config.Memory.CheckInterval, describeRateLimits(config.Memory.EffectiveRateLimits()))
+	}
+	for _, metric := range []struct {
+		name   string
+		config MonitoringConfig
+	}{{"Disk", config.Disk}, {"CPU", config.CPU}, {"Memory", config.Memory}} {
+		if !metric.config.Enabled {
+			continue
+		}
+		for _, change := range metric.config.ChangeThresholds {
+			fmt.Printf("  • %s change threshold (%s points)\n", metric.name, change)
+		}
+		if metric.config.Anomaly.Enabled {
+			fmt.Printf("  • %s anomaly detection (%.1f sigma for %d minutes, half life %d minutes)\n",
+				metric.name, metric.config.Anomaly.Sigma, metric.config.Anomaly.SustainedMinutes, metric.config.Anomaly.HalfLifeMinutes)
+		}
+	}
+	for _, rule := range config.Rules {
+		fmt.Printf("  • Rule %s: %s (every %s, max alerts: %s)\n",
+			rule.Name, rule.Expr, config.RuleInterval, describeRateLimits(rule.EffectiveRateLimits()))
+	}
+	for _, check := range config.Reachability {
+		fmt.Printf("  • Reachability %s: %s (every %s, max alerts: %s)\n",
+			check.Name, check.Address, formatInterval(check.EffectiveInterval()), describeRateLimits(check.EffectiveRateLimits()))
 	}
 
 	// Show notification providers
@@ -212,10 +253,74 @@ func showStatus(config *Config) {
 					fmt.Printf("  • Telegram: %s\n", notification.ChatID)
 				case string(NotificationTypeDiscord):
 					fmt.Printf("  • Discord: %s\n", notification.WebhookURL)
+				case string(NotificationTypeTeams):
+					fmt.Printf("  • Teams: %s\n", notification.WebhookURL)
+				case string(NotificationTypeGoogleChat):
+					fmt.Printf("  • Google Chat: %s\n", notification.WebhookURL)
+				case string(NotificationTypeWebhook):
+					fmt.Printf("  • Webhook: %s\n", notification.WebhookURL)
+				case string(NotificationTypeEmail):
+					fmt.Printf("  • Email: %s via %s\n", strings.Join(notification.To, ", "), notification.Host)
+				case string(NotificationTypePagerDuty):
+					fmt.Printf("  • PagerDuty: routing key %s\n", maskSecret(notification.RoutingKey))
+				case string(NotificationTypeOpsgenie):
+					fmt.Printf("  • Opsgenie: API key %s (%s)\n", maskSecret(notification.APIKey),
+						NewOpsgenieProvider(notification.APIKey, notification.Region, notification.URL, nil).BaseURL)
+				case string(NotificationTypeNtfy):
+					fmt.Printf("  • ntfy: %s\n", notification.URL)
+				case string(NotificationTypeGotify):
+					fmt.Printf("  • Gotify: %s\n", notification.URL)
+				case string(NotificationTypeCommand):
+					fmt.Printf("  • Command %s: %s\n", notification.ProviderName(),
+						strings.Join(append([]string{notification.Command}, notification.Args...), " "))
+				case string(NotificationTypeSyslog):
+					syslog := NewSyslogProvider(notification.Network, notification.Address, notification.Facility, notification.CAFile,
+						notification.Template)
+					fmt.Printf("  • Syslog: %s://%s (%s)\n", syslog.Network, syslog.Address, syslog.Facility)
+				case string(NotificationTypeJournald):
+					fmt.Printf("  • Journald: %s\n", NewJournaldProvider(notification.Address, nil, TemplateConfig{}).Socket)
+				case string(NotificationTypeMatrix):
+					fmt.Printf("  • Matrix: %s on %s\n", notification.RoomID, notification.URL)
+				case string(NotificationTypePushover):
+					fmt.Printf("  • Pushover: user key %s\n", maskSecret(notification.UserKey))
 				}
 			}
 		}
 	}
+
+	// Show alert routes
+	if len(config.Routes) > 0 {
+		fmt.Println("\n🧭 Alert Routes:")
+		for _, route := range config.Routes {
+			fmt.Printf("  • %s → %s\n", describeRouteMatch(route.Match), strings.Join(route.Providers, ", "))
+		}
+	}
+}
+
+// describeRouteMatch returns a short human readable summary of a route match
+func describeRouteMatch(match RouteMatch) string {
+	var parts []string
+	if len(match.Metrics) > 0 {
+		parts = append(parts, "metric="+strings.Join(match.Metrics, "|"))
+	}
+	if len(match.Levels) > 0 {
+		parts = append(parts, "level="+strings.Join(match.Levels, "|"))
+	}
+	if len(match.Checks) > 0 {
+		parts = append(parts, "check="+strings.Join(match.Checks, "|"))
+	}
+	keys := make([]string, 0, len(match.Labels))
+	for key := range match.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		parts = append(parts, key+"="+match.Labels[key])
+	}
+	if len(parts) == 0 {
+		return "all alerts"
+	}
+	return strings.Join(parts, " ")
 }
 
 // runStop stops the monitoring service
@@ -367,10 +472,34 @@ func runDaemon(cmd *cobra.Command, args []string) {
 	// Wait for shutdown signal
 	<-sigChan
 	logger.Println("Received shutdown signal, stopping daemon...")
+	monitor.NotifyShutdown(10 * time.Second)
 	monitor.Stop()
 	logger.Println("ServerHealth daemon stopped")
 }
 
+// Ack command implementation
+func runAck(cmd *cobra.Command, args []string) {
+	alertID := args[0]
+
+	socket, _ := cmd.Flags().GetString("socket")
+	if socket == "" {
+		// Without a readable configuration fall back to the default socket
+		config := NewConfig()
+		if err := LoadConfig(config); err != nil {
+			socket = getControlSocket()
+		} else {
+			socket = config.GetControlSocket()
+		}
+	}
+
+	if _, err := sendControlCommand(socket, "ack "+alertID); err != nil {
+		fmt.Println(red("Failed to acknowledge alert:"), err)
+		os.Exit(1)
+	}
+
+	fmt.Println(green("✅ Alert " + alertID + " acknowledged, escalation stopped."))
+}
+
 // Helper function to stop daemon process
 func stopDaemonProcess(pidFile string) error {
 	// Read PID from file
@@ -508,3 +637,11 @@ func getLogDir() string {
 func getPIDFile() string {
 	return filepath.Join(getPIDDir(), appName+".pid")
 }
+
+// maskSecret hides all but the last four characters of a key or token
+func maskSecret(secret string) string {
+	if len(secret) <= 4 {
+		return strings.Repeat("*", len(secret))
+	}
+	return strings.Repeat("*", 8) + secret[len(secret)-4:]
+}
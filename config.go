@@ -3,32 +3,331 @@ package main
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
+	"os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 const (
 	configFileName = "config"
+
+	// Provider names assigned to migrated legacy Slack webhooks
+	legacySlackDiskName      = "slack-disk"
+	legacySlackCPUMemoryName = "slack-cpu-memory"
 )
 
 // NotificationConfig represents notification provider configuration
 type NotificationConfig struct {
+	Name       string `mapstructure:"name" yaml:"name,omitempty"`
 	Type       string `mapstructure:"type" yaml:"type"`
 	Enabled    bool   `mapstructure:"enabled" yaml:"enabled"`
 	WebhookURL string `mapstructure:"webhook_url" yaml:"webhook_url,omitempty"`
 	BotToken   string `mapstructure:"bot_token" yaml:"bot_token,omitempty"`
 	ChatID     string `mapstructure:"chat_id" yaml:"chat_id,omitempty"`
+
+	// Generic webhook settings; the URL is webhook_url
+	Method  string            `mapstructure:"method" yaml:"method,omitempty"`
+	Headers map[string]string `mapstructure:"headers" yaml:"headers,omitempty"`
+	Payload string            `mapstructure:"payload" yaml:"payload,omitempty"`
+
+	// Email (SMTP) settings
+	Host     string   `mapstructure:"host" yaml:"host,omitempty"`
+	Port     int      `mapstructure:"port" yaml:"port,omitempty"`
+	From     string   `mapstructure:"from" yaml:"from,omitempty"`
+	To       []string `mapstructure:"to" yaml:"to,omitempty"`
+	Username string   `mapstructure:"username" yaml:"username,omitempty"`
+	Password string   `mapstructure:"password" yaml:"password,omitempty"`
+	TLS      string   `mapstructure:"tls" yaml:"tls,omitempty"`
+	Auth     string   `mapstructure:"auth" yaml:"auth,omitempty"`
+
+	// Server or topic URL for ntfy, Gotify and Matrix; API endpoint override
+	// for PagerDuty, Opsgenie and Pushover
+	URL string `mapstructure:"url" yaml:"url,omitempty"`
+
+	// Access token for ntfy (optional), Gotify and Matrix, application token for Pushover
+	Token string `mapstructure:"token" yaml:"token,omitempty"`
+
+	// Matrix room the alerts are posted to
+	RoomID string `mapstructure:"room_id" yaml:"room_id,omitempty"`
+
+	// PagerDuty settings
+	RoutingKey string `mapstructure:"routing_key" yaml:"routing_key,omitempty"`
+
+	// Opsgenie settings
+	APIKey string `mapstructure:"api_key" yaml:"api_key,omitempty"`
+	Region string `mapstructure:"region" yaml:"region,omitempty"`
+
+	// Command settings; the alert is passed as JSON on stdin and in the environment
+	Command        string   `mapstructure:"command" yaml:"command,omitempty"`
+	Args           []string `mapstructure:"args" yaml:"args,omitempty"`
+	TimeoutSeconds int      `mapstructure:"timeout_seconds" yaml:"timeout_seconds,omitempty"`
+	MaxConcurrent  int      `mapstructure:"max_concurrent" yaml:"max_concurrent,omitempty"`
+	ReportResult   bool     `mapstructure:"report_result" yaml:"report_result,omitempty"`
+
+	// Syslog settings; address is host:port, or a socket path for unix (also
+	// overrides the journald socket). The CA file is also used for email TLS.
+	Network  string `mapstructure:"network" yaml:"network,omitempty"`
+	Address  string `mapstructure:"address" yaml:"address,omitempty"`
+	Facility string `mapstructure:"facility" yaml:"facility,omitempty"`
+	CAFile   string `mapstructure:"ca_file" yaml:"ca_file,omitempty"`
+
+	// Extra fields added to every journald entry
+	Fields map[string]string `mapstructure:"fields" yaml:"fields,omitempty"`
+
+	// Pushover settings; retry and expire apply to emergency (error) alerts
+	UserKey       string `mapstructure:"user_key" yaml:"user_key,omitempty"`
+	RetrySeconds  int    `mapstructure:"retry_seconds" yaml:"retry_seconds,omitempty"`
+	ExpireSeconds int    `mapstructure:"expire_seconds" yaml:"expire_seconds,omitempty"`
+
+	// Title and body templates for providers that send text; see TemplateConfig
+	Template TemplateConfig `mapstructure:"template" yaml:"template,omitempty"`
+
+	RateLimits []RateLimit `mapstructure:"rate_limits" yaml:"rate_limits,omitempty"`
+}
+
+// ProviderName returns the name used to reference the provider from routes
+func (n NotificationConfig) ProviderName() string {
+	if n.Name != "" {
+		return n.Name
+	}
+	return n.Type
+}
+
+// TimeWindow represents a recurring time range, e.g. 01:00-05:00 on weekdays
+type TimeWindow struct {
+	Days     []string `mapstructure:"days" yaml:"days,omitempty"`
+	Start    string   `mapstructure:"start" yaml:"start,omitempty"`
+	End      string   `mapstructure:"end" yaml:"end,omitempty"`
+	Timezone string   `mapstructure:"timezone" yaml:"timezone,omitempty"`
+}
+
+// RouteMatch represents the conditions an alert must meet for a route to apply
+type RouteMatch struct {
+	Metrics []string          `mapstructure:"metrics" yaml:"metrics,omitempty"`
+	Levels  []string          `mapstructure:"levels" yaml:"levels,omitempty"`
+	Checks  []string          `mapstructure:"checks" yaml:"checks,omitempty"`
+	Labels  map[string]string `mapstructure:"labels" yaml:"labels,omitempty"`
+	During  []TimeWindow      `mapstructure:"during" yaml:"during,omitempty"`
+}
+
+// RouteConfig represents an alert routing rule
+type RouteConfig struct {
+	Match     RouteMatch `mapstructure:"match" yaml:"match"`
+	Providers []string   `mapstructure:"providers" yaml:"providers"`
+	Continue  bool       `mapstructure:"continue" yaml:"continue,omitempty"`
+}
+
+// EscalationTier represents a set of providers notified when an alert stays unacknowledged
+type EscalationTier struct {
+	AfterMinutes int      `mapstructure:"after_minutes" yaml:"after_minutes"`
+	Providers    []string `mapstructure:"providers" yaml:"providers"`
+}
+
+// EscalationPolicy represents an escalation chain for error-level alerts
+type EscalationPolicy struct {
+	Name  string           `mapstructure:"name" yaml:"name"`
+	Match RouteMatch       `mapstructure:"match" yaml:"match"`
+	Tiers []EscalationTier `mapstructure:"tiers" yaml:"tiers"`
+}
+
+// InhibitRule suppresses alerts matching Target while an alert matching Source is firing
+type InhibitRule struct {
+	Source RouteMatch `mapstructure:"source" yaml:"source"`
+	Target RouteMatch `mapstructure:"target" yaml:"target"`
+}
+
+// FlappingConfig represents flapping detection configuration
+type FlappingConfig struct {
+	Enabled       bool `mapstructure:"enabled" yaml:"enabled"`
+	WindowMinutes int  `mapstructure:"window_minutes" yaml:"window_minutes"`
+	Threshold     int  `mapstructure:"threshold" yaml:"threshold"`
+}
+
+// DigestConfig represents periodic digest report configuration
+type DigestConfig struct {
+	Enabled   bool     `mapstructure:"enabled" yaml:"enabled"`
+	Schedule  string   `mapstructure:"schedule" yaml:"schedule"`
+	Time      string   `mapstructure:"time" yaml:"time"`
+	Weekday   string   `mapstructure:"weekday" yaml:"weekday,omitempty"`
+	Providers []string `mapstructure:"providers" yaml:"providers,omitempty"`
+}
+
+// HeartbeatConfig represents dead man's switch heartbeat configuration
+type HeartbeatConfig struct {
+	Enabled          bool   `mapstructure:"enabled" yaml:"enabled"`
+	URL              string `mapstructure:"url" yaml:"url"`
+	Method           string `mapstructure:"method" yaml:"method"`
+	Interval         string `mapstructure:"interval" yaml:"interval"`
+	NotifyOnShutdown bool   `mapstructure:"notify_on_shutdown" yaml:"notify_on_shutdown"`
+
+	// Deprecated: use Interval
+	IntervalMinutes int `mapstructure:"interval_minutes" yaml:"interval_minutes,omitempty"`
+}
+
+// ControlConfig configures the daemon control socket used by commands such as ack
+type ControlConfig struct {
+	// Socket overrides the per-user default path, so other users can reach a
+	// daemon running as root
+	Socket string `mapstructure:"socket" yaml:"socket,omitempty"`
+	// Group, when set, owns the socket and may send commands to it
+	Group string `mapstructure:"group" yaml:"group,omitempty"`
+}
+
+// GroupingConfig represents alert grouping configuration
+type GroupingConfig struct {
+	Enabled bool   `mapstructure:"enabled" yaml:"enabled"`
+	Window  string `mapstructure:"window" yaml:"window"`
+
+	// Deprecated: use Window
+	WindowSeconds int `mapstructure:"window_seconds" yaml:"window_seconds,omitempty"`
+}
+
+// RateLimit represents a sliding-window alert limit, e.g. at most 3 alerts per 60 minutes
+type RateLimit struct {
+	Max           int `mapstructure:"max" yaml:"max"`
+	WindowMinutes int `mapstructure:"window_minutes" yaml:"window_minutes"`
+}
+
+// ScheduleOverride changes a check's threshold and providers during a time window
+type ScheduleOverride struct {
+	Name       string `mapstructure:"name" yaml:"name,omitempty"`
+	TimeWindow `mapstructure:",squash" yaml:",inline"`
+	Threshold  int      `mapstructure:"threshold" yaml:"threshold,omitempty"`
+	Providers  []string `mapstructure:"providers" yaml:"providers,omitempty"`
+}
+
+// ChangeThreshold represents a rate-of-change limit, e.g. a rise of 30 points within 5 minutes.
+// A negative delta matches a drop of at least that size.
+type ChangeThreshold struct {
+	Delta         float64 `mapstructure:"delta" yaml:"delta"`
+	WindowMinutes int     `mapstructure:"window_minutes" yaml:"window_minutes"`
+	Level         string  `mapstructure:"level" yaml:"level,omitempty"`
+}
+
+// AnomalyConfig represents statistical anomaly detection for a metric
+type AnomalyConfig struct {
+	Enabled          bool    `mapstructure:"enabled" yaml:"enabled"`
+	Sigma            float64 `mapstructure:"sigma" yaml:"sigma"`
+	HalfLifeMinutes  int     `mapstructure:"half_life_minutes" yaml:"half_life_minutes"`
+	SustainedMinutes int     `mapstructure:"sustained_minutes" yaml:"sustained_minutes"`
+	MinSamples       int     `mapstructure:"min_samples" yaml:"min_samples"`
+}
+
+// defaultAnomalyConfig is the anomaly detection configuration used when none is given
+var defaultAnomalyConfig = AnomalyConfig{
+	Enabled:          false,
+	Sigma:            3,
+	HalfLifeMinutes:  24 * 60,
+	SustainedMinutes: 15,
+	MinSamples:       30,
 }
 
 // MonitoringConfig represents monitoring configuration
 type MonitoringConfig struct {
-	Enabled        bool `mapstructure:"enabled" yaml:"enabled"`
-	Threshold      int  `mapstructure:"threshold" yaml:"threshold"`
-	CheckInterval  int  `mapstructure:"check_interval" yaml:"check_interval"`
-	MaxDailyAlerts int  `mapstructure:"max_daily_alerts" yaml:"max_daily_alerts"`
+	Enabled       bool        `mapstructure:"enabled" yaml:"enabled"`
+	Threshold     int         `mapstructure:"threshold" yaml:"threshold"`
+	CheckInterval string      `mapstructure:"check_interval" yaml:"check_interval"`
+	Jitter        string      `mapstructure:"jitter" yaml:"jitter,omitempty"`
+	RateLimits    []RateLimit `mapstructure:"rate_limits" yaml:"rate_limits,omitempty"`
+
+	// Threshold and routing overrides for specific times of day or weekdays
+	Schedules []ScheduleOverride `mapstructure:"schedules" yaml:"schedules,omitempty"`
+
+	// Checks that must be healthy for this check's result to be meaningful
+	DependsOn []string `mapstructure:"depends_on" yaml:"depends_on,omitempty"`
+
+	// Alert when the value changes too quickly
+	ChangeThresholds []ChangeThreshold `mapstructure:"change_thresholds" yaml:"change_thresholds,omitempty"`
+
+	// Alert when the value deviates from its learned baseline
+	Anomaly AnomalyConfig `mapstructure:"anomaly" yaml:"anomaly"`
+
+	// Deprecated: use RateLimits. Treated as a sliding 24 hour limit.
+	MaxDailyAlerts int `mapstructure:"max_daily_alerts" yaml:"max_daily_alerts,omitempty"`
+}
+
+// EffectiveRateLimits returns the configured rate limits, falling back to the
+// legacy daily maximum as a sliding 24 hour window
+func (mc MonitoringConfig) EffectiveRateLimits() []RateLimit {
+	if len(mc.RateLimits) > 0 {
+		return mc.RateLimits
+	}
+	if mc.MaxDailyAlerts > 0 {
+		return []RateLimit{{Max: mc.MaxDailyAlerts, WindowMinutes: 24 * 60}}
+	}
+	return nil
+}
+
+// Interval returns how often the check runs, or 0 if the interval is invalid
+func (mc MonitoringConfig) Interval() time.Duration {
+	return parseInterval(mc.CheckInterval)
+}
+
+// MaxJitter returns the largest random delay added before the first check
+func (mc MonitoringConfig) MaxJitter() time.Duration {
+	return parseInterval(mc.Jitter)
+}
+
+// RuleConfig represents a custom alert rule that fires while its expression is true
+type RuleConfig struct {
+	Name       string            `mapstructure:"name" yaml:"name"`
+	Expr       string            `mapstructure:"expr" yaml:"expr"`
+	Level      string            `mapstructure:"level" yaml:"level,omitempty"`
+	Message    string            `mapstructure:"message" yaml:"message,omitempty"`
+	Labels     map[string]string `mapstructure:"labels" yaml:"labels,omitempty"`
+	RateLimits []RateLimit       `mapstructure:"rate_limits" yaml:"rate_limits,omitempty"`
+	DependsOn  []string          `mapstructure:"depends_on" yaml:"depends_on,omitempty"`
+}
+
+// EffectiveRateLimits returns the rule's rate limits, defaulting to 5 alerts per 24 hours
+func (rc RuleConfig) EffectiveRateLimits() []RateLimit {
+	if len(rc.RateLimits) > 0 {
+		return rc.RateLimits
+	}
+	return []RateLimit{{Max: 5, WindowMinutes: 24 * 60}}
+}
+
+// ReachabilityConfig is a TCP endpoint, such as the default gateway, that fires
+// while it refuses connections. Other checks can depend on it.
+type ReachabilityConfig struct {
+	Name       string            `mapstructure:"name" yaml:"name"`
+	Address    string            `mapstructure:"address" yaml:"address"`
+	Interval   string            `mapstructure:"interval" yaml:"interval,omitempty"`
+	Timeout    string            `mapstructure:"timeout" yaml:"timeout,omitempty"`
+	Level      string            `mapstructure:"level" yaml:"level,omitempty"`
+	Labels     map[string]string `mapstructure:"labels" yaml:"labels,omitempty"`
+	RateLimits []RateLimit       `mapstructure:"rate_limits" yaml:"rate_limits,omitempty"`
+	DependsOn  []string          `mapstructure:"depends_on" yaml:"depends_on,omitempty"`
+}
+
+// EffectiveInterval returns how often the endpoint is dialled, defaulting to one minute
+func (rc ReachabilityConfig) EffectiveInterval() time.Duration {
+	if rc.Interval == "" {
+		return time.Minute
+	}
+	return parseInterval(rc.Interval)
+}
+
+// EffectiveTimeout returns how long a dial may take, defaulting to five seconds
+func (rc ReachabilityConfig) EffectiveTimeout() time.Duration {
+	if rc.Timeout == "" {
+		return 5 * time.Second
+	}
+	return parseInterval(rc.Timeout)
+}
+
+// EffectiveRateLimits returns the check's rate limits, defaulting to 5 alerts per 24 hours
+func (rc ReachabilityConfig) EffectiveRateLimits() []RateLimit {
+	if len(rc.RateLimits) > 0 {
+		return rc.RateLimits
+	}
+	return []RateLimit{{Max: 5, WindowMinutes: 24 * 60}}
 }
 
 // Config represents the application configuration
@@ -40,10 +339,39 @@ type Config struct {
 
 	// Notification settings
 	Notifications []NotificationConfig `mapstructure:"notifications" yaml:"notifications"`
+	Routes        []RouteConfig        `mapstructure:"routes" yaml:"routes,omitempty"`
+	Escalations   []EscalationPolicy   `mapstructure:"escalation_policies" yaml:"escalation_policies,omitempty"`
+	InhibitRules  []InhibitRule        `mapstructure:"inhibit_rules" yaml:"inhibit_rules,omitempty"`
+
+	// Custom expression-based alert rules
+	Rules        []RuleConfig `mapstructure:"rules" yaml:"rules,omitempty"`
+	RuleInterval string       `mapstructure:"rule_interval" yaml:"rule_interval"`
+
+	// TCP reachability checks
+	Reachability []ReachabilityConfig `mapstructure:"reachability" yaml:"reachability,omitempty"`
+
+	// Alert grouping
+	Grouping GroupingConfig `mapstructure:"grouping" yaml:"grouping"`
+
+	// Flapping detection
+	Flapping FlappingConfig `mapstructure:"flapping" yaml:"flapping"`
+
+	// Periodic digest reports
+	Digest DigestConfig `mapstructure:"digest" yaml:"digest"`
+
+	// Dead man's switch heartbeat
+	Heartbeat HeartbeatConfig `mapstructure:"heartbeat" yaml:"heartbeat"`
+
+	// Control socket for alert acknowledgements
+	Control ControlConfig `mapstructure:"control" yaml:"control,omitempty"`
+
+	// Host labels attached to every alert
+	Labels map[string]string `mapstructure:"labels" yaml:"labels,omitempty"`
 
 	// General settings
 	LogLevel    string `mapstructure:"log_level" yaml:"log_level"`
 	ServiceName string `mapstructure:"service_name" yaml:"service_name"`
+	DataDir     string `mapstructure:"data_dir" yaml:"data_dir,omitempty"`
 
 	// Legacy support (deprecated)
 	SlackDiskWebhookURL      string `mapstructure:"slack_disk_webhook_url" yaml:"slack_disk_webhook_url,omitempty"`
@@ -56,6 +384,7 @@ type Config struct {
 	MemoryThreshold          int    `mapstructure:"memory_threshold" yaml:"memory_threshold,omitempty"`
 	CheckInterval            int    `mapstructure:"check_interval_minutes" yaml:"check_interval_minutes,omitempty"`
 	DiskCheckInterval        int    `mapstructure:"disk_check_interval_hours" yaml:"disk_check_interval_hours,omitempty"`
+	RuleIntervalSeconds      int    `mapstructure:"rule_interval_seconds" yaml:"rule_interval_seconds,omitempty"`
 }
 
 // NewConfig creates a new configuration with default values
@@ -64,24 +393,48 @@ func NewConfig() *Config {
 		Disk: MonitoringConfig{
 			Enabled:        true,
 			Threshold:      80,
-			CheckInterval:  1, // hours
+			CheckInterval:  "1h",
 			MaxDailyAlerts: 5,
+			Anomaly:        defaultAnomalyConfig,
 		},
 		CPU: MonitoringConfig{
 			Enabled:        true,
 			Threshold:      90,
-			CheckInterval:  1, // minutes
+			CheckInterval:  "1m",
 			MaxDailyAlerts: 5,
+			Anomaly:        defaultAnomalyConfig,
 		},
 		Memory: MonitoringConfig{
 			Enabled:        true,
 			Threshold:      90,
-			CheckInterval:  1, // minutes
+			CheckInterval:  "1m",
 			MaxDailyAlerts: 5,
+			Anomaly:        defaultAnomalyConfig,
 		},
 		Notifications: []NotificationConfig{},
-		LogLevel:      "info",
-		ServiceName:   appName,
+		RuleInterval:  "1m",
+		Grouping: GroupingConfig{
+			Enabled: false,
+			Window:  "30s",
+		},
+		Flapping: FlappingConfig{
+			Enabled:       false,
+			WindowMinutes: 30,
+			Threshold:     6,
+		},
+		Digest: DigestConfig{
+			Enabled:  false,
+			Schedule: "daily",
+			Time:     "08:00",
+			Weekday:  "monday",
+		},
+		Heartbeat: HeartbeatConfig{
+			Enabled:  false,
+			Method:   "POST",
+			Interval: "5m",
+		},
+		LogLevel:    "info",
+		ServiceName: appName,
 	}
 }
 
@@ -108,19 +461,46 @@ func LoadConfig(config *Config) error {
 	// Set defaults
 	viper.SetDefault("disk.enabled", true)
 	viper.SetDefault("disk.threshold", 80)
-	viper.SetDefault("disk.check_interval", 12)
+	viper.SetDefault("disk.check_interval", "12h")
 	viper.SetDefault("disk.max_daily_alerts", 5)
 
 	viper.SetDefault("cpu.enabled", true)
 	viper.SetDefault("cpu.threshold", 85)
-	viper.SetDefault("cpu.check_interval", 60)
+	viper.SetDefault("cpu.check_interval", "1h")
 	viper.SetDefault("cpu.max_daily_alerts", 5)
 
 	viper.SetDefault("memory.enabled", true)
 	viper.SetDefault("memory.threshold", 85)
-	viper.SetDefault("memory.check_interval", 60)
+	viper.SetDefault("memory.check_interval", "1h")
 	viper.SetDefault("memory.max_daily_alerts", 5)
 
+	for _, metric := range []string{"disk", "cpu", "memory"} {
+		viper.SetDefault(metric+".anomaly.enabled", defaultAnomalyConfig.Enabled)
+		viper.SetDefault(metric+".anomaly.sigma", defaultAnomalyConfig.Sigma)
+		viper.SetDefault(metric+".anomaly.half_life_minutes", defaultAnomalyConfig.HalfLifeMinutes)
+		viper.SetDefault(metric+".anomaly.sustained_minutes", defaultAnomalyConfig.SustainedMinutes)
+		viper.SetDefault(metric+".anomaly.min_samples", defaultAnomalyConfig.MinSamples)
+	}
+
+	viper.SetDefault("rule_interval", "1m")
+
+	viper.SetDefault("grouping.enabled", false)
+	viper.SetDefault("grouping.window", "30s")
+
+	viper.SetDefault("flapping.enabled", false)
+	viper.SetDefault("flapping.window_minutes", 30)
+	viper.SetDefault("flapping.threshold", 6)
+
+	viper.SetDefault("digest.enabled", false)
+	viper.SetDefault("digest.schedule", "daily")
+	viper.SetDefault("digest.time", "08:00")
+	viper.SetDefault("digest.weekday", "monday")
+
+	viper.SetDefault("heartbeat.enabled", false)
+	viper.SetDefault("heartbeat.method", "POST")
+	viper.SetDefault("heartbeat.interval", "5m")
+	viper.SetDefault("heartbeat.notify_on_shutdown", false)
+
 	viper.SetDefault("log_level", "info")
 	viper.SetDefault("service_name", appName)
 
@@ -138,6 +518,7 @@ func LoadConfig(config *Config) error {
 
 	// Migrate legacy configuration
 	config.migrateLegacyConfig()
+	config.normalizeIntervals()
 
 	return nil
 }
@@ -151,7 +532,7 @@ func (c *Config) migrateLegacyConfig() {
 			c.Disk.Threshold = c.DiskThreshold
 		}
 		if c.DiskCheckInterval > 0 {
-			c.Disk.CheckInterval = c.DiskCheckInterval
+			c.Disk.CheckInterval = strconv.Itoa(c.DiskCheckInterval)
 		}
 	}
 
@@ -162,7 +543,7 @@ func (c *Config) migrateLegacyConfig() {
 			c.CPU.Threshold = c.CPUThreshold
 		}
 		if c.CheckInterval > 0 {
-			c.CPU.CheckInterval = c.CheckInterval
+			c.CPU.CheckInterval = strconv.Itoa(c.CheckInterval)
 		}
 	}
 
@@ -173,30 +554,123 @@ func (c *Config) migrateLegacyConfig() {
 			c.Memory.Threshold = c.MemoryThreshold
 		}
 		if c.CheckInterval > 0 {
-			c.Memory.CheckInterval = c.CheckInterval
+			c.Memory.CheckInterval = strconv.Itoa(c.CheckInterval)
 		}
 	}
 
-	// Migrate legacy Slack notifications
+	// Migrate legacy Slack notifications, routing each metric to its webhook.
+	// Other enabled providers stay on the routes so they keep every alert.
 	if c.SlackDiskWebhookURL != "" || c.SlackCPUMemoryWebhookURL != "" {
+		var others []string
+		for _, notification := range c.Notifications {
+			name := notification.ProviderName()
+			if notification.Enabled && name != legacySlackDiskName && name != legacySlackCPUMemoryName {
+				others = append(others, name)
+			}
+		}
+		if len(others) > 0 {
+			log.Printf("Warning: legacy Slack webhooks are deprecated; their alerts are also routed to %s. "+
+				"Define notifications and routes instead.", strings.Join(others, ", "))
+		}
+
 		// Add Slack notification for disk
-		if c.SlackDiskWebhookURL != "" {
+		if c.SlackDiskWebhookURL != "" && !c.hasNotification(legacySlackDiskName) {
 			c.Notifications = append(c.Notifications, NotificationConfig{
+				Name:       legacySlackDiskName,
 				Type:       "slack",
 				Enabled:    true,
 				WebhookURL: c.SlackDiskWebhookURL,
 			})
+			c.Routes = append(c.Routes, RouteConfig{
+				Match:     RouteMatch{Metrics: []string{"disk"}},
+				Providers: append([]string{legacySlackDiskName}, others...),
+				Continue:  true,
+			})
 		}
 
 		// Add Slack notification for CPU/Memory
-		if c.SlackCPUMemoryWebhookURL != "" {
+		if c.SlackCPUMemoryWebhookURL != "" && !c.hasNotification(legacySlackCPUMemoryName) {
 			c.Notifications = append(c.Notifications, NotificationConfig{
+				Name:       legacySlackCPUMemoryName,
 				Type:       "slack",
 				Enabled:    true,
 				WebhookURL: c.SlackCPUMemoryWebhookURL,
 			})
+			c.Routes = append(c.Routes, RouteConfig{
+				Match:     RouteMatch{Metrics: []string{"cpu", "memory"}},
+				Providers: append([]string{legacySlackCPUMemoryName}, others...),
+				Continue:  true,
+			})
+		}
+	}
+}
+
+// normalizeIntervals converts legacy integer intervals to duration strings.
+// Bare numbers keep their old units: hours for the disk check, minutes for the
+// CPU and memory checks and the heartbeat, and seconds for rules and grouping.
+// The deprecated *_seconds and *_minutes keys take precedence when set.
+func (c *Config) normalizeIntervals() {
+	c.Disk.CheckInterval = normalizeInterval(c.Disk.CheckInterval, time.Hour)
+	c.CPU.CheckInterval = normalizeInterval(c.CPU.CheckInterval, time.Minute)
+	c.Memory.CheckInterval = normalizeInterval(c.Memory.CheckInterval, time.Minute)
+
+	if c.RuleIntervalSeconds > 0 {
+		c.RuleInterval = strconv.Itoa(c.RuleIntervalSeconds)
+		c.RuleIntervalSeconds = 0
+	}
+	c.RuleInterval = normalizeInterval(c.RuleInterval, time.Second)
+
+	if c.Grouping.WindowSeconds > 0 {
+		c.Grouping.Window = strconv.Itoa(c.Grouping.WindowSeconds)
+		c.Grouping.WindowSeconds = 0
+	}
+	c.Grouping.Window = normalizeInterval(c.Grouping.Window, time.Second)
+
+	if c.Heartbeat.IntervalMinutes > 0 {
+		c.Heartbeat.Interval = strconv.Itoa(c.Heartbeat.IntervalMinutes)
+		c.Heartbeat.IntervalMinutes = 0
+	}
+	c.Heartbeat.Interval = normalizeInterval(c.Heartbeat.Interval, time.Minute)
+}
+
+// normalizeInterval returns value as a duration string, reading a bare integer in legacyUnit
+func normalizeInterval(value string, legacyUnit time.Duration) string {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return value
+	}
+	return formatInterval(time.Duration(n) * legacyUnit)
+}
+
+// parseInterval returns a duration string as a duration, or 0 if it is invalid
+func parseInterval(value string) time.Duration {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0
+	}
+	return interval
+}
+
+// formatInterval returns a compact duration string such as "30s", "5m" or "12h"
+func formatInterval(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// hasNotification reports whether a notification provider with the given name exists
+func (c *Config) hasNotification(name string) bool {
+	for _, notification := range c.Notifications {
+		if notification.ProviderName() == name {
+			return true
 		}
 	}
+	return false
 }
 
 // SaveConfig saves configuration to file
@@ -211,8 +685,21 @@ func SaveConfig(config *Config) error {
 	viper.Set("cpu", config.CPU)
 	viper.Set("memory", config.Memory)
 	viper.Set("notifications", config.Notifications)
+	viper.Set("routes", config.Routes)
+	viper.Set("escalation_policies", config.Escalations)
+	viper.Set("inhibit_rules", config.InhibitRules)
+	viper.Set("rules", config.Rules)
+	viper.Set("reachability", config.Reachability)
+	viper.Set("rule_interval", config.RuleInterval)
+	viper.Set("grouping", config.Grouping)
+	viper.Set("flapping", config.Flapping)
+	viper.Set("digest", config.Digest)
+	viper.Set("heartbeat", config.Heartbeat)
+	viper.Set("control", config.Control)
+	viper.Set("labels", config.Labels)
 	viper.Set("log_level", config.LogLevel)
 	viper.Set("service_name", config.ServiceName)
+	viper.Set("data_dir", config.DataDir)
 
 	configFile := filepath.Join(configDir, configFileName+".yaml")
 	return viper.WriteConfigAs(configFile)
@@ -223,8 +710,8 @@ func (c *Config) Validate() error {
 	var errors []string
 
 	// Check if at least one monitoring option is enabled
-	if !c.Disk.Enabled && !c.CPU.Enabled && !c.Memory.Enabled {
-		errors = append(errors, "at least one monitoring option must be enabled")
+	if !c.Disk.Enabled && !c.CPU.Enabled && !c.Memory.Enabled && len(c.Rules) == 0 && len(c.Reachability) == 0 {
+		errors = append(errors, "at least one monitoring option, rule or reachability check must be enabled")
 	}
 
 	// Validate disk monitoring configuration
@@ -232,12 +719,31 @@ func (c *Config) Validate() error {
 		if c.Disk.Threshold < 1 || c.Disk.Threshold > 100 {
 			errors = append(errors, "disk threshold must be between 1 and 100")
 		}
-		if c.Disk.CheckInterval < 1 || c.Disk.CheckInterval > 168 {
-			errors = append(errors, "disk check interval must be between 1 and 168 hours")
+		if err := validateCheckInterval(c.Disk.CheckInterval, c.Disk.Jitter); err != nil {
+			errors = append(errors, fmt.Sprintf("disk %v", err))
 		}
-		if c.Disk.MaxDailyAlerts < 1 || c.Disk.MaxDailyAlerts > 100 {
+		if len(c.Disk.RateLimits) == 0 && (c.Disk.MaxDailyAlerts < 1 || c.Disk.MaxDailyAlerts > 100) {
 			errors = append(errors, "disk max daily alerts must be between 1 and 100")
 		}
+		if err := validateRateLimits(c.Disk.RateLimits); err != nil {
+			errors = append(errors, fmt.Sprintf("disk rate limits: %v", err))
+		}
+		for i, schedule := range c.Disk.Schedules {
+			if err := c.validateSchedule(&schedule); err != nil {
+				errors = append(errors, fmt.Sprintf("disk schedule %d: %v", i+1, err))
+			}
+		}
+		interval := c.Disk.Interval()
+		for i, change := range c.Disk.ChangeThresholds {
+			if err := validateChangeThreshold(&change, interval); err != nil {
+				errors = append(errors, fmt.Sprintf("disk change threshold %d: %v", i+1, err))
+			}
+		}
+		if c.Disk.Anomaly.Enabled {
+			if err := validateAnomaly(&c.Disk.Anomaly); err != nil {
+				errors = append(errors, fmt.Sprintf("disk anomaly detection: %v", err))
+			}
+		}
 	}
 
 	// Validate CPU monitoring configuration
@@ -245,12 +751,31 @@ func (c *Config) Validate() error {
 		if c.CPU.Threshold < 1 || c.CPU.Threshold > 100 {
 			errors = append(errors, "CPU threshold must be between 1 and 100")
 		}
-		if c.CPU.CheckInterval < 1 || c.CPU.CheckInterval > 1440 {
-			errors = append(errors, "CPU check interval must be between 1 and 1440 minutes")
+		if err := validateCheckInterval(c.CPU.CheckInterval, c.CPU.Jitter); err != nil {
+			errors = append(errors, fmt.Sprintf("CPU %v", err))
 		}
-		if c.CPU.MaxDailyAlerts < 1 || c.CPU.MaxDailyAlerts > 100 {
+		if len(c.CPU.RateLimits) == 0 && (c.CPU.MaxDailyAlerts < 1 || c.CPU.MaxDailyAlerts > 100) {
 			errors = append(errors, "CPU max daily alerts must be between 1 and 100")
 		}
+		if err := validateRateLimits(c.CPU.RateLimits); err != nil {
+			errors = append(errors, fmt.Sprintf("CPU rate limits: %v", err))
+		}
+		for i, schedule := range c.CPU.Schedules {
+			if err := c.validateSchedule(&schedule); err != nil {
+				errors = append(errors, fmt.Sprintf("CPU schedule %d: %v", i+1, err))
+			}
+		}
+		interval := c.CPU.Interval()
+		for i, change := range c.CPU.ChangeThresholds {
+			if err := validateChangeThreshold(&change, interval); err != nil {
+				errors = append(errors, fmt.Sprintf("CPU change threshold %d: %v", i+1, err))
+			}
+		}
+		if c.CPU.Anomaly.Enabled {
+			if err := validateAnomaly(&c.CPU.Anomaly); err != nil {
+				errors = append(errors, fmt.Sprintf("CPU anomaly detection: %v", err))
+			}
+		}
 	}
 
 	// Validate memory monitoring configuration
@@ -258,12 +783,116 @@ func (c *Config) Validate() error {
 		if c.Memory.Threshold < 1 || c.Memory.Threshold > 100 {
 			errors = append(errors, "memory threshold must be between 1 and 100")
 		}
-		if c.Memory.CheckInterval < 1 || c.Memory.CheckInterval > 1440 {
-			errors = append(errors, "memory check interval must be between 1 and 1440 minutes")
+		if err := validateCheckInterval(c.Memory.CheckInterval, c.Memory.Jitter); err != nil {
+			errors = append(errors, fmt.Sprintf("memory %v", err))
 		}
-		if c.Memory.MaxDailyAlerts < 1 || c.Memory.MaxDailyAlerts > 100 {
+		if len(c.Memory.RateLimits) == 0 && (c.Memory.MaxDailyAlerts < 1 || c.Memory.MaxDailyAlerts > 100) {
 			errors = append(errors, "memory max daily alerts must be between 1 and 100")
 		}
+		if err := validateRateLimits(c.Memory.RateLimits); err != nil {
+			errors = append(errors, fmt.Sprintf("memory rate limits: %v", err))
+		}
+		for i, schedule := range c.Memory.Schedules {
+			if err := c.validateSchedule(&schedule); err != nil {
+				errors = append(errors, fmt.Sprintf("memory schedule %d: %v", i+1, err))
+			}
+		}
+		interval := c.Memory.Interval()
+		for i, change := range c.Memory.ChangeThresholds {
+			if err := validateChangeThreshold(&change, interval); err != nil {
+				errors = append(errors, fmt.Sprintf("memory change threshold %d: %v", i+1, err))
+			}
+		}
+		if c.Memory.Anomaly.Enabled {
+			if err := validateAnomaly(&c.Memory.Anomaly); err != nil {
+				errors = append(errors, fmt.Sprintf("memory anomaly detection: %v", err))
+			}
+		}
+	}
+
+	// Validate custom alert rules
+	if len(c.Rules) > 0 {
+		if err := validateInterval(c.RuleInterval, 10*time.Second, time.Hour); err != nil {
+			errors = append(errors, fmt.Sprintf("rule interval: %v", err))
+		}
+	}
+	ruleNames := make(map[string]bool)
+	for i, rule := range c.Rules {
+		if err := validateRule(&rule); err != nil {
+			errors = append(errors, fmt.Sprintf("rule %d (%s): %v", i+1, rule.Name, err))
+		} else if ruleNames[rule.Name] {
+			errors = append(errors, fmt.Sprintf("rule %d (%s): duplicate rule name", i+1, rule.Name))
+		}
+		ruleNames[rule.Name] = true
+	}
+
+	// Validate reachability checks, whose names share the rule namespace
+	for i, check := range c.Reachability {
+		if err := validateReachability(&check); err != nil {
+			errors = append(errors, fmt.Sprintf("reachability check %d (%s): %v", i+1, check.Name, err))
+		} else if ruleNames[check.Name] {
+			errors = append(errors, fmt.Sprintf("reachability check %d (%s): duplicate check name", i+1, check.Name))
+		}
+		ruleNames[check.Name] = true
+	}
+
+	// Validate check dependencies
+	if err := c.validateDependencies(); err != nil {
+		errors = append(errors, fmt.Sprintf("dependencies: %v", err))
+	}
+
+	// Validate inhibition rules
+	for i, rule := range c.InhibitRules {
+		if err := validateInhibitRule(&rule); err != nil {
+			errors = append(errors, fmt.Sprintf("inhibit rule %d: %v", i+1, err))
+		}
+	}
+
+	// Validate alert grouping configuration
+	if c.Grouping.Enabled {
+		if err := validateInterval(c.Grouping.Window, time.Second, time.Hour); err != nil {
+			errors = append(errors, fmt.Sprintf("grouping window: %v", err))
+		}
+	}
+
+	// Validate flapping detection configuration
+	if c.Flapping.Enabled {
+		if c.Flapping.WindowMinutes < 1 || c.Flapping.WindowMinutes > 1440 {
+			errors = append(errors, "flapping window must be between 1 and 1440 minutes")
+		}
+		if c.Flapping.Threshold < 2 || c.Flapping.Threshold > 100 {
+			errors = append(errors, "flapping threshold must be between 2 and 100 state changes")
+		}
+	}
+
+	// Validate digest configuration
+	if c.Digest.Enabled {
+		if err := c.validateDigest(); err != nil {
+			errors = append(errors, fmt.Sprintf("digest: %v", err))
+		}
+	}
+
+	// Validate heartbeat configuration
+	if c.Heartbeat.Enabled {
+		if !strings.HasPrefix(c.Heartbeat.URL, "https://") && !strings.HasPrefix(c.Heartbeat.URL, "http://") {
+			errors = append(errors, "heartbeat URL must be an http:// or https:// URL")
+		}
+		if c.Heartbeat.Method != "GET" && c.Heartbeat.Method != "POST" {
+			errors = append(errors, "heartbeat method must be GET or POST")
+		}
+		if err := validateInterval(c.Heartbeat.Interval, time.Minute, 24*time.Hour); err != nil {
+			errors = append(errors, fmt.Sprintf("heartbeat interval: %v", err))
+		}
+	}
+
+	// Validate control socket configuration
+	if c.Control.Socket != "" && !filepath.IsAbs(c.Control.Socket) {
+		errors = append(errors, "control socket must be an absolute path")
+	}
+	if c.Control.Group != "" {
+		if _, err := user.LookupGroup(c.Control.Group); err != nil {
+			errors = append(errors, fmt.Sprintf("control group %q not found", c.Control.Group))
+		}
 	}
 
 	// Validate log level
@@ -284,17 +913,39 @@ func (c *Config) Validate() error {
 
 	// Validate notifications
 	enabledNotifications := 0
+	providerNames := make(map[string]bool)
 	for i, notification := range c.Notifications {
 		if notification.Enabled {
 			enabledNotifications++
 			if err := c.validateNotification(&notification); err != nil {
 				errors = append(errors, fmt.Sprintf("notification %d (%s): %v", i+1, notification.Type, err))
 			}
+			// Routes, escalations and rate limits refer to providers by name
+			name := notification.ProviderName()
+			if providerNames[name] {
+				errors = append(errors, fmt.Sprintf("notification %d (%s): duplicate provider name %q; set a unique name",
+					i+1, notification.Type, name))
+			}
+			providerNames[name] = true
+		}
+	}
+
+	// Validate routes
+	for i, route := range c.Routes {
+		if err := c.validateRoute(&route); err != nil {
+			errors = append(errors, fmt.Sprintf("route %d: %v", i+1, err))
+		}
+	}
+
+	// Validate escalation policies
+	for i, policy := range c.Escalations {
+		if err := c.validateEscalationPolicy(&policy); err != nil {
+			errors = append(errors, fmt.Sprintf("escalation policy %d (%s): %v", i+1, policy.Name, err))
 		}
 	}
 
 	// Check if we have at least one enabled notification if monitoring is enabled
-	if (c.Disk.Enabled || c.CPU.Enabled || c.Memory.Enabled) && enabledNotifications == 0 {
+	if (c.Disk.Enabled || c.CPU.Enabled || c.Memory.Enabled || len(c.Rules) > 0 || len(c.Reachability) > 0) && enabledNotifications == 0 {
 		errors = append(errors, "at least one notification provider must be enabled when monitoring is enabled")
 	}
 
@@ -305,8 +956,121 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// validateSchedule validates a single schedule override
+func (c *Config) validateSchedule(schedule *ScheduleOverride) error {
+	if err := schedule.validate(); err != nil {
+		return err
+	}
+	if schedule.Threshold != 0 && (schedule.Threshold < 1 || schedule.Threshold > 100) {
+		return fmt.Errorf("threshold must be between 1 and 100")
+	}
+	for _, name := range schedule.Providers {
+		if !c.hasNotification(name) {
+			return fmt.Errorf("unknown provider: %s", name)
+		}
+	}
+	return nil
+}
+
+// validateInterval checks that value is a duration between min and max
+func validateInterval(value string, min, max time.Duration) error {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%q is not a valid duration (e.g. 30s, 5m, 1h)", value)
+	}
+	if interval < min || interval > max {
+		return fmt.Errorf("must be between %s and %s", formatInterval(min), formatInterval(max))
+	}
+	return nil
+}
+
+// validateCheckInterval validates a check interval and its optional jitter
+func validateCheckInterval(value, jitter string) error {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("check interval %q is not a valid duration (e.g. 30s, 5m, 12h)", value)
+	}
+	if interval < 5*time.Second || interval > 7*24*time.Hour {
+		return fmt.Errorf("check interval must be between 5s and 168h")
+	}
+
+	if jitter != "" {
+		spread, err := time.ParseDuration(jitter)
+		if err != nil {
+			return fmt.Errorf("jitter %q is not a valid duration (e.g. 10s, 2m)", jitter)
+		}
+		if spread < 0 || spread >= interval {
+			return fmt.Errorf("jitter must be at least 0 and less than the check interval")
+		}
+	}
+
+	return nil
+}
+
+// validateChangeThreshold validates a rate-of-change threshold against the check interval
+func validateChangeThreshold(change *ChangeThreshold, interval time.Duration) error {
+	if change.Delta == 0 || change.Delta < -100 || change.Delta > 100 {
+		return fmt.Errorf("delta must be between -100 and 100 and not 0")
+	}
+	if change.WindowMinutes < 1 || change.WindowMinutes > 7*24*60 {
+		return fmt.Errorf("window must be between 1 and 10080 minutes")
+	}
+	if time.Duration(change.WindowMinutes)*time.Minute < interval {
+		return fmt.Errorf("window must be at least the check interval (%s)", formatInterval(interval))
+	}
+	if change.Level != "" && !isValidNotificationLevel(change.Level) {
+		return fmt.Errorf("invalid level %q (must be one of: info, warning, error)", change.Level)
+	}
+	return nil
+}
+
+// validateAnomaly validates anomaly detection settings
+func validateAnomaly(anomaly *AnomalyConfig) error {
+	if anomaly.Sigma < 1 || anomaly.Sigma > 10 {
+		return fmt.Errorf("sigma must be between 1 and 10")
+	}
+	if anomaly.HalfLifeMinutes < 1 || anomaly.HalfLifeMinutes > 30*24*60 {
+		return fmt.Errorf("half life must be between 1 and 43200 minutes")
+	}
+	if anomaly.SustainedMinutes < 0 || anomaly.SustainedMinutes > 24*60 {
+		return fmt.Errorf("sustained period must be between 0 and 1440 minutes")
+	}
+	if anomaly.MinSamples < 2 || anomaly.MinSamples > 10000 {
+		return fmt.Errorf("min samples must be between 2 and 10000")
+	}
+	return nil
+}
+
+// validateRateLimits validates a list of sliding-window rate limits
+func validateRateLimits(limits []RateLimit) error {
+	for i, limit := range limits {
+		if limit.Max < 1 || limit.Max > 1000 {
+			return fmt.Errorf("limit %d: max must be between 1 and 1000", i+1)
+		}
+		if limit.WindowMinutes < 1 || limit.WindowMinutes > 7*24*60 {
+			return fmt.Errorf("limit %d: window must be between 1 and 10080 minutes", i+1)
+		}
+	}
+	return nil
+}
+
 // validateNotification validates a single notification configuration
 func (c *Config) validateNotification(notification *NotificationConfig) error {
+	if err := validateRateLimits(notification.RateLimits); err != nil {
+		return fmt.Errorf("rate limits: %w", err)
+	}
+
+	// Structured providers build their own payloads and have no text to template
+	if !notification.Template.IsZero() {
+		switch notification.Type {
+		case "webhook", "pagerduty", "opsgenie", "command":
+			return fmt.Errorf("templates are not supported for %s notifications", notification.Type)
+		}
+		if _, err := notification.Template.Load(); err != nil {
+			return fmt.Errorf("template: %w", err)
+		}
+	}
+
 	switch notification.Type {
 	case "slack":
 		if notification.WebhookURL == "" {
@@ -329,6 +1093,123 @@ func (c *Config) validateNotification(notification *NotificationConfig) error {
 		if !strings.Contains(notification.WebhookURL, "discord.com") && !strings.Contains(notification.WebhookURL, "discordapp.com") {
 			return fmt.Errorf("webhook URL must be from discord.com or discordapp.com")
 		}
+	case "teams":
+		if notification.WebhookURL == "" {
+			return fmt.Errorf("webhook URL is required for Teams notifications")
+		}
+		if err := NewTeamsProvider(notification.WebhookURL, notification.Template, nil).Validate(); err != nil {
+			return err
+		}
+	case "googlechat":
+		if notification.WebhookURL == "" {
+			return fmt.Errorf("webhook URL is required for Google Chat notifications")
+		}
+		if err := NewGoogleChatProvider(notification.WebhookURL, notification.Template, nil).Validate(); err != nil {
+			return err
+		}
+	case "webhook":
+		if notification.WebhookURL == "" {
+			return fmt.Errorf("webhook URL is required for webhook notifications")
+		}
+		provider := NewWebhookProvider(notification.WebhookURL, notification.Method, notification.Headers, notification.Payload, nil)
+		if err := provider.Validate(); err != nil {
+			return err
+		}
+	case "email":
+		if notification.Host == "" {
+			return fmt.Errorf("host is required for email notifications")
+		}
+		if notification.From == "" {
+			return fmt.Errorf("from address is required for email notifications")
+		}
+		if len(notification.To) == 0 {
+			return fmt.Errorf("at least one recipient (to) is required for email notifications")
+		}
+		provider := NewEmailProvider(notification.Host, notification.Port, notification.From, notification.To,
+			notification.Username, notification.Password, notification.TLS, notification.Auth, notification.CAFile, notification.Template)
+		if err := provider.Validate(); err != nil {
+			return err
+		}
+	case "pagerduty":
+		if notification.RoutingKey == "" {
+			return fmt.Errorf("routing key is required for PagerDuty notifications")
+		}
+		provider := NewPagerDutyProvider(notification.RoutingKey, notification.URL, nil)
+		if err := provider.Validate(); err != nil {
+			return err
+		}
+	case "opsgenie":
+		if notification.APIKey == "" {
+			return fmt.Errorf("API key is required for Opsgenie notifications")
+		}
+		if notification.Region != "" && !strings.EqualFold(notification.Region, "us") && !strings.EqualFold(notification.Region, "eu") {
+			return fmt.Errorf("region must be one of: us, eu")
+		}
+		provider := NewOpsgenieProvider(notification.APIKey, notification.Region, notification.URL, nil)
+		if err := provider.Validate(); err != nil {
+			return err
+		}
+	case "ntfy":
+		if notification.URL == "" {
+			return fmt.Errorf("topic URL (url) is required for ntfy notifications")
+		}
+		if err := NewNtfyProvider(notification.URL, notification.Token, notification.Template, nil).Validate(); err != nil {
+			return err
+		}
+	case "gotify":
+		if notification.URL == "" {
+			return fmt.Errorf("server URL (url) is required for Gotify notifications")
+		}
+		if notification.Token == "" {
+			return fmt.Errorf("application token is required for Gotify notifications")
+		}
+		if err := NewGotifyProvider(notification.URL, notification.Token, notification.Template, nil).Validate(); err != nil {
+			return err
+		}
+	case "command":
+		if notification.Command == "" {
+			return fmt.Errorf("command is required for command notifications")
+		}
+		if notification.TimeoutSeconds < 0 {
+			return fmt.Errorf("timeout_seconds must be positive")
+		}
+		if notification.MaxConcurrent < 0 {
+			return fmt.Errorf("max_concurrent must be at least 1")
+		}
+		provider := NewCommandProvider(notification.ProviderName(), notification.Command, notification.Args,
+			time.Duration(notification.TimeoutSeconds)*time.Second, notification.MaxConcurrent, nil)
+		if err := provider.Validate(); err != nil {
+			return err
+		}
+	case "syslog":
+		provider := NewSyslogProvider(notification.Network, notification.Address, notification.Facility, notification.CAFile,
+			notification.Template)
+		if err := provider.Validate(); err != nil {
+			return err
+		}
+	case "journald":
+		if err := NewJournaldProvider(notification.Address, notification.Fields, notification.Template).Validate(); err != nil {
+			return err
+		}
+	case "matrix":
+		if notification.URL == "" {
+			return fmt.Errorf("homeserver URL (url) is required for Matrix notifications")
+		}
+		if err := NewMatrixProvider(notification.URL, notification.Token, notification.RoomID, notification.Template, nil).Validate(); err != nil {
+			return err
+		}
+	case "pushover":
+		if notification.Token == "" {
+			return fmt.Errorf("application token is required for Pushover notifications")
+		}
+		if notification.UserKey == "" {
+			return fmt.Errorf("user key is required for Pushover notifications")
+		}
+		provider := NewPushoverProvider(notification.Token, notification.UserKey, notification.RetrySeconds,
+			notification.ExpireSeconds, notification.URL, notification.Template, nil)
+		if err := provider.Validate(); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unsupported notification type: %s", notification.Type)
 	}
@@ -336,6 +1217,206 @@ func (c *Config) validateNotification(notification *NotificationConfig) error {
 	return nil
 }
 
+// validateRoute validates a single routing rule
+func (c *Config) validateRoute(route *RouteConfig) error {
+	if len(route.Providers) == 0 {
+		return fmt.Errorf("at least one provider is required")
+	}
+
+	for _, name := range route.Providers {
+		if !c.hasNotification(name) {
+			return fmt.Errorf("unknown provider: %s", name)
+		}
+	}
+
+	for _, level := range route.Match.Levels {
+		if !isValidNotificationLevel(level) {
+			return fmt.Errorf("invalid level %q (must be one of: info, warning, error)", level)
+		}
+	}
+
+	for _, window := range route.Match.During {
+		if err := window.validate(); err != nil {
+			return fmt.Errorf("during: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateEscalationPolicy validates a single escalation policy
+func (c *Config) validateEscalationPolicy(policy *EscalationPolicy) error {
+	if policy.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(policy.Tiers) == 0 {
+		return fmt.Errorf("at least one tier is required")
+	}
+
+	previous := 0
+	for i, tier := range policy.Tiers {
+		if tier.AfterMinutes <= previous {
+			return fmt.Errorf("tier %d: after_minutes must be greater than %d", i+1, previous)
+		}
+		previous = tier.AfterMinutes
+
+		if len(tier.Providers) == 0 {
+			return fmt.Errorf("tier %d: at least one provider is required", i+1)
+		}
+		for _, name := range tier.Providers {
+			if !c.hasNotification(name) {
+				return fmt.Errorf("tier %d: unknown provider: %s", i+1, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateRule validates a single custom alert rule and its expression
+func validateRule(rule *RuleConfig) error {
+	if rule.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if isBuiltinCheckName(rule.Name) {
+		return fmt.Errorf("name %q is reserved for a built-in check", rule.Name)
+	}
+
+	if rule.Level != "" && !isValidNotificationLevel(rule.Level) {
+		return fmt.Errorf("invalid level %q (must be one of: info, warning, error)", rule.Level)
+	}
+
+	if err := validateRateLimits(rule.RateLimits); err != nil {
+		return fmt.Errorf("rate limits: %w", err)
+	}
+
+	if rule.Expr == "" {
+		return fmt.Errorf("expr is required")
+	}
+	expr, err := ParseExpr(rule.Expr)
+	if err != nil {
+		return fmt.Errorf("invalid expr: %w", err)
+	}
+	for _, metric := range exprMetrics(expr) {
+		if _, ok := ruleMetricSources[metric]; !ok {
+			return fmt.Errorf("unknown metric %s (must be one of: %s)", metric, strings.Join(ruleMetricNames(), ", "))
+		}
+	}
+
+	return nil
+}
+
+// isBuiltinCheckName reports whether name is a built-in check or one derived from a metric
+func isBuiltinCheckName(name string) bool {
+	for metricKey := range metricSeries {
+		if name == metricKey || name == anomalyCheckKey(metricKey) || name == changeCheckKey(metricKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateReachability validates a single TCP reachability check
+func validateReachability(check *ReachabilityConfig) error {
+	if check.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if isBuiltinCheckName(check.Name) {
+		return fmt.Errorf("name %q is reserved for a built-in check", check.Name)
+	}
+
+	if check.Address == "" {
+		return fmt.Errorf("address is required")
+	}
+	if host, port, err := net.SplitHostPort(check.Address); err != nil || host == "" || port == "" {
+		return fmt.Errorf("address %q must be host:port", check.Address)
+	}
+
+	if check.Interval != "" {
+		if err := validateInterval(check.Interval, 10*time.Second, time.Hour); err != nil {
+			return fmt.Errorf("interval: %w", err)
+		}
+	}
+	if check.Timeout != "" {
+		if err := validateInterval(check.Timeout, time.Second, time.Minute); err != nil {
+			return fmt.Errorf("timeout: %w", err)
+		}
+		if check.EffectiveTimeout() >= check.EffectiveInterval() {
+			return fmt.Errorf("timeout must be shorter than the interval")
+		}
+	}
+
+	if check.Level != "" && !isValidNotificationLevel(check.Level) {
+		return fmt.Errorf("invalid level %q (must be one of: info, warning, error)", check.Level)
+	}
+
+	if err := validateRateLimits(check.RateLimits); err != nil {
+		return fmt.Errorf("rate limits: %w", err)
+	}
+
+	return nil
+}
+
+// validateInhibitRule validates a single inhibition rule
+func validateInhibitRule(rule *InhibitRule) error {
+	if rule.Source.isEmpty() {
+		return fmt.Errorf("source must have at least one condition")
+	}
+	if rule.Target.isEmpty() {
+		return fmt.Errorf("target must have at least one condition")
+	}
+
+	for _, level := range append(append([]string{}, rule.Source.Levels...), rule.Target.Levels...) {
+		if !isValidNotificationLevel(level) {
+			return fmt.Errorf("invalid level %q (must be one of: info, warning, error)", level)
+		}
+	}
+
+	return nil
+}
+
+// validateDigest validates the digest report configuration
+func (c *Config) validateDigest() error {
+	switch c.Digest.Schedule {
+	case "daily":
+	case "weekly":
+		if _, err := parseWeekday(c.Digest.Weekday); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("schedule must be one of: daily, weekly")
+	}
+
+	if _, _, err := parseClock(c.Digest.Time); err != nil {
+		return err
+	}
+
+	for _, name := range c.Digest.Providers {
+		if !c.hasNotification(name) {
+			return fmt.Errorf("unknown provider: %s", name)
+		}
+	}
+
+	return nil
+}
+
+// GetDataDir returns the directory used for persistent state
+func (c *Config) GetDataDir() string {
+	if c.DataDir != "" {
+		return c.DataDir
+	}
+	return getDataDir()
+}
+
+// GetControlSocket returns the path of the daemon control socket
+func (c *Config) GetControlSocket() string {
+	if c.Control.Socket != "" {
+		return c.Control.Socket
+	}
+	return getControlSocket()
+}
+
 // GetEnabledNotifications returns all enabled notification providers
 func (c *Config) GetEnabledNotifications() []NotificationConfig {
 	var enabled []NotificationConfig
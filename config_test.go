@@ -0,0 +1,180 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizeIntervals(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+		want   [6]string // disk, cpu, memory, rules, grouping, heartbeat
+	}{
+		{
+			name: "durations",
+			config: Config{
+				Disk: MonitoringConfig{CheckInterval: "12h"}, CPU: MonitoringConfig{CheckInterval: "15s"},
+				Memory: MonitoringConfig{CheckInterval: "1m"}, RuleInterval: "30s",
+				Grouping: GroupingConfig{Window: "45s"}, Heartbeat: HeartbeatConfig{Interval: "2m"},
+			},
+			want: [6]string{"12h", "15s", "1m", "30s", "45s", "2m"},
+		},
+		{
+			name: "bare numbers keep their old units",
+			config: Config{
+				Disk: MonitoringConfig{CheckInterval: "6"}, CPU: MonitoringConfig{CheckInterval: "5"},
+				Memory: MonitoringConfig{CheckInterval: "10"}, RuleInterval: "90",
+				Grouping: GroupingConfig{Window: "30"}, Heartbeat: HeartbeatConfig{Interval: "5"},
+			},
+			want: [6]string{"6h", "5m", "10m", "1m30s", "30s", "5m"},
+		},
+		{
+			name: "deprecated keys take precedence",
+			config: Config{
+				Disk: MonitoringConfig{CheckInterval: "1h"}, CPU: MonitoringConfig{CheckInterval: "1m"},
+				Memory: MonitoringConfig{CheckInterval: "1m"}, RuleInterval: "1m", RuleIntervalSeconds: 120,
+				Grouping:  GroupingConfig{Window: "30s", WindowSeconds: 10},
+				Heartbeat: HeartbeatConfig{Interval: "5m", IntervalMinutes: 15},
+			},
+			want: [6]string{"1h", "1m", "1m", "2m", "10s", "15m"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.config
+			c.normalizeIntervals()
+			got := [6]string{c.Disk.CheckInterval, c.CPU.CheckInterval, c.Memory.CheckInterval,
+				c.RuleInterval, c.Grouping.Window, c.Heartbeat.Interval}
+			if got != tt.want {
+				t.Errorf("normalizeIntervals() = %v, want %v", got, tt.want)
+			}
+			if c.RuleIntervalSeconds != 0 || c.Grouping.WindowSeconds != 0 || c.Heartbeat.IntervalMinutes != 0 {
+				t.Error("deprecated keys were not cleared")
+			}
+		})
+	}
+}
+
+func TestValidateInterval(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"30s", false},
+		{"10s", false},
+		{"1h", false},
+		{"5s", true},
+		{"2h", true},
+		{"60", true},
+		{"", true},
+	}
+	for _, tt := range tests {
+		err := validateInterval(tt.value, 10*time.Second, time.Hour)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateInterval(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestMigrateLegacySlackKeepsOtherProviders(t *testing.T) {
+	tests := []struct {
+		name          string
+		notifications []NotificationConfig
+		want          map[string][]string // check -> providers
+	}{
+		{
+			name: "legacy webhooks only",
+			want: map[string][]string{
+				"disk":   {legacySlackDiskName},
+				"memory": {legacySlackCPUMemoryName},
+				"rule":   nil,
+			},
+		},
+		{
+			name: "with another provider",
+			notifications: []NotificationConfig{
+				{Type: "telegram", Enabled: true},
+				{Type: "discord", Enabled: false},
+			},
+			want: map[string][]string{
+				"disk":   {legacySlackDiskName, "telegram"},
+				"memory": {legacySlackCPUMemoryName, "telegram"},
+				"rule":   nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				Notifications:            tt.notifications,
+				SlackDiskWebhookURL:      "https://hooks.slack.com/services/disk",
+				SlackCPUMemoryWebhookURL: "https://hooks.slack.com/services/cpu",
+			}
+			config.migrateLegacyConfig()
+
+			router := NewRouter(config.Routes)
+			for check, want := range tt.want {
+				message := &NotificationMessage{Check: check, Labels: map[string]string{"metric": check}}
+				if got := router.Route(message); !reflect.DeepEqual(got, want) {
+					t.Errorf("Route(%s) = %v, want %v", check, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateRejectsDuplicateProviderNames(t *testing.T) {
+	const webhook = "https://hooks.slack.com/services/T000/B000/XXXX"
+	tests := []struct {
+		name          string
+		notifications []NotificationConfig
+		wantErr       bool
+	}{
+		{
+			name: "same type without names",
+			notifications: []NotificationConfig{
+				{Type: "slack", Enabled: true, WebhookURL: webhook},
+				{Type: "slack", Enabled: true, WebhookURL: webhook},
+			},
+			wantErr: true,
+		},
+		{
+			name: "name reused by another type",
+			notifications: []NotificationConfig{
+				{Name: "ops", Type: "slack", Enabled: true, WebhookURL: webhook},
+				{Name: "ops", Type: "discord", Enabled: true, WebhookURL: "https://discord.com/api/webhooks/1/x"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unique names",
+			notifications: []NotificationConfig{
+				{Name: "ops", Type: "slack", Enabled: true, WebhookURL: webhook},
+				{Name: "dev", Type: "slack", Enabled: true, WebhookURL: webhook},
+			},
+		},
+		{
+			name: "disabled duplicate",
+			notifications: []NotificationConfig{
+				{Type: "slack", Enabled: true, WebhookURL: webhook},
+				{Type: "slack", Enabled: false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewConfig()
+			config.Notifications = tt.notifications
+			err := config.Validate()
+			if gotErr := err != nil && strings.Contains(err.Error(), "duplicate provider name"); gotErr != tt.wantErr {
+				t.Errorf("Validate() error = %v, want duplicate error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Validate() error = %v", err)
+			}
+		})
+	}
+}
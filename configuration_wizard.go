@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"net/mail"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/manifoldco/promptui"
 )
@@ -173,6 +175,16 @@ func (w *ConfigurationWizard) configureSlackWebhooks(config *Config) error {
 		{"Slack", "Send notifications to Slack channels", "slack"},
 		{"Telegram", "Send notifications to Telegram chat", "telegram"},
 		{"Discord", "Send notifications to Discord channels", "discord"},
+		{"Microsoft Teams", "Send notifications to Teams channels", "teams"},
+		{"Google Chat", "Send notifications to Google Chat spaces", "googlechat"},
+		{"Matrix", "Send notifications to a Matrix room", "matrix"},
+		{"Email", "Send notifications by email over SMTP", "email"},
+		{"ntfy", "Send push notifications through an ntfy topic", "ntfy"},
+		{"Gotify", "Send push notifications to a Gotify server", "gotify"},
+		{"Pushover", "Send push notifications through Pushover", "pushover"},
+		{"Webhook", "Send notifications to any HTTP endpoint", "webhook"},
+		{"PagerDuty", "Page on-call through the PagerDuty Events API", "pagerduty"},
+		{"Opsgenie", "Create alerts through the Opsgenie Alerts API", "opsgenie"},
 	}
 
 	fmt.Println(blue("📝 Instructions:"))
@@ -286,6 +298,26 @@ func (w *ConfigurationWizard) configureNotificationProvider(config *Config, prov
 		return w.configureTelegramProvider(notification)
 	case "discord":
 		return w.configureDiscordProvider(notification)
+	case "teams":
+		return w.configureTeamsProvider(notification)
+	case "googlechat":
+		return w.configureGoogleChatProvider(notification)
+	case "matrix":
+		return w.configureMatrixProvider(notification)
+	case "email":
+		return w.configureEmailProvider(notification)
+	case "ntfy":
+		return w.configureNtfyProvider(notification)
+	case "gotify":
+		return w.configureGotifyProvider(notification)
+	case "pushover":
+		return w.configurePushoverProvider(notification)
+	case "webhook":
+		return w.configureWebhookProvider(notification)
+	case "pagerduty":
+		return w.configurePagerDutyProvider(notification)
+	case "opsgenie":
+		return w.configureOpsgenieProvider(notification)
 	default:
 		return fmt.Errorf("unsupported provider type: %s", providerType)
 	}
@@ -411,6 +443,530 @@ func (w *ConfigurationWizard) configureDiscordProvider(notification *Notificatio
 	return nil
 }
 
+func (w *ConfigurationWizard) configureTeamsProvider(notification *NotificationConfig) error {
+	fmt.Println("Microsoft Teams uses webhook URLs to send notifications.")
+	fmt.Println("To create a webhook:")
+	fmt.Println("1. Open the channel's ••• menu in Teams")
+	fmt.Println("2. Choose Workflows > 'Post to a channel when a webhook request is received'")
+	fmt.Println("   (or Connectors > Incoming Webhook)")
+	fmt.Println("3. Copy the webhook URL")
+	fmt.Println()
+
+	prompt := promptui.Prompt{
+		Label:   "Enter Teams webhook URL",
+		Default: notification.WebhookURL,
+		Validate: func(input string) error {
+			if input == "" {
+				return fmt.Errorf("webhook URL cannot be empty")
+			}
+			if !strings.HasPrefix(input, "https://") {
+				return fmt.Errorf("webhook URL must use HTTPS")
+			}
+			return nil
+		},
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+
+	notification.WebhookURL = result
+	notification.Enabled = true
+
+	fmt.Println(green("✅ Teams notification configured successfully!"))
+	return nil
+}
+
+func (w *ConfigurationWizard) configureGoogleChatProvider(notification *NotificationConfig) error {
+	fmt.Println("Google Chat uses space webhook URLs to send notifications.")
+	fmt.Println("To create a webhook:")
+	fmt.Println("1. Open the space in Google Chat")
+	fmt.Println("2. Go to Apps & integrations > Webhooks")
+	fmt.Println("3. Add a webhook and copy its URL")
+	fmt.Println()
+
+	prompt := promptui.Prompt{
+		Label:   "Enter Google Chat webhook URL",
+		Default: notification.WebhookURL,
+		Validate: func(input string) error {
+			if input == "" {
+				return fmt.Errorf("webhook URL cannot be empty")
+			}
+			if !strings.HasPrefix(input, "https://chat.googleapis.com/") {
+				return fmt.Errorf("invalid Google Chat webhook URL format")
+			}
+			return nil
+		},
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+
+	notification.WebhookURL = result
+	notification.Enabled = true
+
+	fmt.Println(green("✅ Google Chat notification configured successfully!"))
+	return nil
+}
+
+func (w *ConfigurationWizard) configureMatrixProvider(notification *NotificationConfig) error {
+	fmt.Println("Matrix notifications are posted to a room by a bot account.")
+	fmt.Println("You will need:")
+	fmt.Println("1. The homeserver URL, e.g. https://matrix.example.org")
+	fmt.Println("2. An access token of the bot account (Element: Settings > Help & About)")
+	fmt.Println("3. The room ID (Element: Room settings > Advanced), with the bot invited")
+	fmt.Println()
+
+	urlPrompt := promptui.Prompt{
+		Label:   "Enter homeserver URL",
+		Default: notification.URL,
+		Validate: func(input string) error {
+			if !strings.HasPrefix(input, "https://") && !strings.HasPrefix(input, "http://") {
+				return fmt.Errorf("homeserver URL must start with http:// or https://")
+			}
+			return nil
+		},
+	}
+	homeserverURL, err := urlPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	tokenPrompt := promptui.Prompt{
+		Label:    "Enter access token",
+		Mask:     '*',
+		Validate: requiredInput("access token"),
+	}
+	if notification.Token != "" {
+		tokenPrompt.Label = "Enter access token (leave empty to keep current)"
+		tokenPrompt.Validate = nil
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		token = notification.Token
+	}
+
+	roomPrompt := promptui.Prompt{
+		Label:   "Enter room ID (e.g. !abcdef:example.org)",
+		Default: notification.RoomID,
+		Validate: func(input string) error {
+			if !strings.HasPrefix(input, "!") || !strings.Contains(input, ":") {
+				return fmt.Errorf("room ID must look like !abcdef:example.org")
+			}
+			return nil
+		},
+	}
+	roomID, err := roomPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	notification.URL = homeserverURL
+	notification.Token = strings.TrimSpace(token)
+	notification.RoomID = roomID
+	notification.Enabled = true
+
+	fmt.Println(green("✅ Matrix notification configured successfully!"))
+	return nil
+}
+
+func (w *ConfigurationWizard) configureEmailProvider(notification *NotificationConfig) error {
+	fmt.Println("Email notifications are sent through an SMTP server.")
+	fmt.Println("You will need:")
+	fmt.Println("1. The SMTP host and port of your mail provider")
+	fmt.Println("2. A sender address and one or more recipients")
+	fmt.Println("3. SMTP credentials, if your server requires them")
+	fmt.Println()
+
+	// SMTP host
+	hostPrompt := promptui.Prompt{
+		Label:    "Enter SMTP host",
+		Default:  notification.Host,
+		Validate: requiredInput("SMTP host"),
+	}
+	host, err := hostPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	// TLS mode
+	tlsModes := []string{EmailTLSStartTLS, EmailTLSImplicit, EmailTLSNone}
+	tlsPrompt := promptui.Select{
+		Label: "Select connection security",
+		Items: []string{
+			"STARTTLS (port 587, recommended)",
+			"Implicit TLS (port 465)",
+			"None (local test servers only)",
+		},
+		HideHelp: true,
+	}
+	tlsIndex, _, err := tlsPrompt.Run()
+	if err != nil {
+		return err
+	}
+	tlsMode := tlsModes[tlsIndex]
+
+	// Port
+	port := notification.Port
+	if port == 0 || notification.TLS != tlsMode {
+		port = defaultEmailPort(tlsMode)
+	}
+	portPrompt := promptui.Prompt{
+		Label:   "Enter SMTP port",
+		Default: strconv.Itoa(port),
+		Validate: func(input string) error {
+			val, err := strconv.Atoi(input)
+			if err != nil || val < 1 || val > 65535 {
+				return fmt.Errorf("port must be a number between 1 and 65535")
+			}
+			return nil
+		},
+	}
+	portResult, err := portPrompt.Run()
+	if err != nil {
+		return err
+	}
+	port, _ = strconv.Atoi(portResult)
+
+	// Sender
+	fromPrompt := promptui.Prompt{
+		Label:   "Enter sender address",
+		Default: notification.From,
+		Validate: func(input string) error {
+			if _, err := mail.ParseAddress(input); err != nil {
+				return fmt.Errorf("invalid email address")
+			}
+			return nil
+		},
+	}
+	from, err := fromPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	// Recipients
+	toPrompt := promptui.Prompt{
+		Label:   "Enter recipient addresses (comma-separated)",
+		Default: strings.Join(notification.To, ", "),
+		Validate: func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return fmt.Errorf("at least one recipient is required")
+			}
+			for _, address := range splitList(input) {
+				if _, err := mail.ParseAddress(address); err != nil {
+					return fmt.Errorf("invalid email address: %s", address)
+				}
+			}
+			return nil
+		},
+	}
+	toResult, err := toPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	// Credentials
+	usernamePrompt := promptui.Prompt{
+		Label:   "Enter SMTP username (leave empty for no authentication)",
+		Default: notification.Username,
+	}
+	username, err := usernamePrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	password := ""
+	if username != "" {
+		passwordPrompt := promptui.Prompt{
+			Label:    "Enter SMTP password",
+			Mask:     '*',
+			Validate: requiredInput("password"),
+		}
+		if notification.Password != "" {
+			passwordPrompt.Label = "Enter SMTP password (leave empty to keep current)"
+			passwordPrompt.Validate = nil
+		}
+		password, err = passwordPrompt.Run()
+		if err != nil {
+			return err
+		}
+		if password == "" {
+			password = notification.Password
+		}
+	}
+
+	notification.Host = strings.TrimSpace(host)
+	notification.Port = port
+	notification.TLS = tlsMode
+	notification.From = from
+	notification.To = splitList(toResult)
+	notification.Username = username
+	notification.Password = password
+	notification.Enabled = true
+
+	fmt.Println(green("✅ Email notification configured successfully!"))
+	return nil
+}
+
+func (w *ConfigurationWizard) configureWebhookProvider(notification *NotificationConfig) error {
+	fmt.Println("Webhooks send each notification to an HTTP endpoint of your choice.")
+	fmt.Println("By default the notification is sent as JSON. Custom headers and a")
+	fmt.Println("payload template can be added to the configuration file afterwards.")
+	fmt.Println()
+
+	prompt := promptui.Prompt{
+		Label:   "Enter webhook URL",
+		Default: notification.WebhookURL,
+		Validate: func(input string) error {
+			if !strings.HasPrefix(input, "https://") && !strings.HasPrefix(input, "http://") {
+				return fmt.Errorf("webhook URL must start with http:// or https://")
+			}
+			return nil
+		},
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+
+	methods := []string{"POST", "PUT", "PATCH", "GET"}
+	methodPrompt := promptui.Select{
+		Label:    "Select HTTP method",
+		Items:    methods,
+		HideHelp: true,
+	}
+	methodIndex, _, err := methodPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	notification.WebhookURL = result
+	notification.Method = methods[methodIndex]
+	notification.Enabled = true
+
+	fmt.Println(green("✅ Webhook notification configured successfully!"))
+	return nil
+}
+
+func (w *ConfigurationWizard) configurePagerDutyProvider(notification *NotificationConfig) error {
+	fmt.Println("PagerDuty alerts are sent with an Events API v2 integration key.")
+	fmt.Println("To get one:")
+	fmt.Println("1. Open your service in PagerDuty → Integrations")
+	fmt.Println("2. Add an 'Events API V2' integration")
+	fmt.Println("3. Copy the Integration Key")
+	fmt.Println()
+
+	prompt := promptui.Prompt{
+		Label:   "Enter integration (routing) key",
+		Default: notification.RoutingKey,
+		Validate: func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return fmt.Errorf("routing key is required")
+			}
+			return nil
+		},
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+
+	notification.RoutingKey = strings.TrimSpace(result)
+	notification.Enabled = true
+
+	fmt.Println(green("✅ PagerDuty notification configured successfully!"))
+	return nil
+}
+
+func (w *ConfigurationWizard) configureOpsgenieProvider(notification *NotificationConfig) error {
+	fmt.Println("Opsgenie alerts are created with an API integration key.")
+	fmt.Println("To get one:")
+	fmt.Println("1. Open your team in Opsgenie → Integrations")
+	fmt.Println("2. Add an 'API' integration")
+	fmt.Println("3. Copy the API Key")
+	fmt.Println()
+
+	prompt := promptui.Prompt{
+		Label:   "Enter API key",
+		Default: notification.APIKey,
+		Validate: func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return fmt.Errorf("API key is required")
+			}
+			return nil
+		},
+	}
+
+	result, err := prompt.Run()
+	if err != nil {
+		return err
+	}
+
+	regions := []string{"us", "eu"}
+	regionPrompt := promptui.Select{
+		Label:    "Select Opsgenie region",
+		Items:    []string{"US (api.opsgenie.com)", "EU (api.eu.opsgenie.com)"},
+		HideHelp: true,
+	}
+	regionIndex, _, err := regionPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	notification.APIKey = strings.TrimSpace(result)
+	notification.Region = regions[regionIndex]
+	notification.Enabled = true
+
+	fmt.Println(green("✅ Opsgenie notification configured successfully!"))
+	return nil
+}
+
+func (w *ConfigurationWizard) configureNtfyProvider(notification *NotificationConfig) error {
+	fmt.Println("ntfy publishes notifications to a topic you subscribe to in the ntfy app.")
+	fmt.Println("Use a hard-to-guess topic name on ntfy.sh, or a topic on your own server.")
+	fmt.Println()
+
+	urlPrompt := promptui.Prompt{
+		Label:   "Enter topic URL (e.g. https://ntfy.sh/my-alerts)",
+		Default: notification.URL,
+		Validate: func(input string) error {
+			return NewNtfyProvider(input, "", TemplateConfig{}, nil).Validate()
+		},
+	}
+	topicURL, err := urlPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	tokenPrompt := promptui.Prompt{
+		Label: "Enter access token (leave empty for public topics)",
+		Mask:  '*',
+	}
+	if notification.Token != "" {
+		tokenPrompt.Label = "Enter access token (leave empty to keep current)"
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		token = notification.Token
+	}
+
+	notification.URL = topicURL
+	notification.Token = token
+	notification.Enabled = true
+
+	fmt.Println(green("✅ ntfy notification configured successfully!"))
+	return nil
+}
+
+func (w *ConfigurationWizard) configureGotifyProvider(notification *NotificationConfig) error {
+	fmt.Println("Gotify notifications are sent with an application token.")
+	fmt.Println("To get one:")
+	fmt.Println("1. Open your Gotify web UI → Apps")
+	fmt.Println("2. Create an application")
+	fmt.Println("3. Copy its token")
+	fmt.Println()
+
+	urlPrompt := promptui.Prompt{
+		Label:   "Enter Gotify server URL",
+		Default: notification.URL,
+		Validate: func(input string) error {
+			if !strings.HasPrefix(input, "https://") && !strings.HasPrefix(input, "http://") {
+				return fmt.Errorf("server URL must start with http:// or https://")
+			}
+			return nil
+		},
+	}
+	serverURL, err := urlPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	tokenPrompt := promptui.Prompt{
+		Label:    "Enter application token",
+		Default:  notification.Token,
+		Validate: requiredInput("application token"),
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	notification.URL = serverURL
+	notification.Token = strings.TrimSpace(token)
+	notification.Enabled = true
+
+	fmt.Println(green("✅ Gotify notification configured successfully!"))
+	return nil
+}
+
+func (w *ConfigurationWizard) configurePushoverProvider(notification *NotificationConfig) error {
+	fmt.Println("Pushover needs your user key and an application token.")
+	fmt.Println("To get them:")
+	fmt.Println("1. Copy your User Key from the Pushover dashboard")
+	fmt.Println("2. Create an application and copy its API Token")
+	fmt.Println("Error alerts use emergency priority and repeat until acknowledged.")
+	fmt.Println()
+
+	userPrompt := promptui.Prompt{
+		Label:    "Enter user key",
+		Default:  notification.UserKey,
+		Validate: requiredInput("user key"),
+	}
+	userKey, err := userPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	tokenPrompt := promptui.Prompt{
+		Label:    "Enter application API token",
+		Default:  notification.Token,
+		Validate: requiredInput("application token"),
+	}
+	token, err := tokenPrompt.Run()
+	if err != nil {
+		return err
+	}
+
+	notification.UserKey = strings.TrimSpace(userKey)
+	notification.Token = strings.TrimSpace(token)
+	notification.Enabled = true
+
+	fmt.Println(green("✅ Pushover notification configured successfully!"))
+	return nil
+}
+
+// requiredInput returns a prompt validator that rejects empty input
+func requiredInput(field string) func(string) error {
+	return func(input string) error {
+		if strings.TrimSpace(input) == "" {
+			return fmt.Errorf("%s cannot be empty", field)
+		}
+		return nil
+	}
+}
+
+// splitList splits a comma-separated list and trims each entry
+func splitList(input string) []string {
+	var items []string
+	for _, item := range strings.Split(input, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 func (w *ConfigurationWizard) configureThresholds(config *Config) error {
 	fmt.Println()
 	fmt.Println(bold("⚠️ Alert Thresholds"))
@@ -598,92 +1154,62 @@ func (w *ConfigurationWizard) configureIntervals(config *Config) error {
 	fmt.Println()
 
 	// Collect enabled metrics that need intervals
-	var intervalNeeds []struct {
+	type intervalNeed struct {
 		Name        string
-		Current     int
-		Default     int
-		Target      *int
-		Unit        string
+		Default     string
+		Target      *string
+		Jitter      *string
 		Description string
-		Min         int
-		Max         int
+		Frequent    time.Duration
+		Infrequent  time.Duration
 	}
+	var intervalNeeds []intervalNeed
 
 	// Add CPU interval if enabled
 	if config.CPU.Enabled {
-		if config.CPU.CheckInterval == 0 {
-			config.CPU.CheckInterval = 60
+		if config.CPU.CheckInterval == "" {
+			config.CPU.CheckInterval = "1h"
 		}
-		intervalNeeds = append(intervalNeeds, struct {
-			Name        string
-			Current     int
-			Default     int
-			Target      *int
-			Unit        string
-			Description string
-			Min         int
-			Max         int
-		}{
+		intervalNeeds = append(intervalNeeds, intervalNeed{
 			Name:        "CPU Check Interval",
-			Current:     config.CPU.CheckInterval,
-			Default:     60,
+			Default:     "1h",
 			Target:      &config.CPU.CheckInterval,
-			Unit:        "minutes",
+			Jitter:      &config.CPU.Jitter,
 			Description: "How often to check CPU usage",
-			Min:         1,
-			Max:         1440, // 24 hours
+			Frequent:    15 * time.Minute,
+			Infrequent:  4 * time.Hour,
 		})
 	}
 
 	// Add Memory interval if enabled
 	if config.Memory.Enabled {
-		if config.Memory.CheckInterval == 0 {
-			config.Memory.CheckInterval = 60
+		if config.Memory.CheckInterval == "" {
+			config.Memory.CheckInterval = "1h"
 		}
-		intervalNeeds = append(intervalNeeds, struct {
-			Name        string
-			Current     int
-			Default     int
-			Target      *int
-			Unit        string
-			Description string
-			Min         int
-			Max         int
-		}{
+		intervalNeeds = append(intervalNeeds, intervalNeed{
 			Name:        "Memory Check Interval",
-			Current:     config.Memory.CheckInterval,
-			Default:     60,
+			Default:     "1h",
 			Target:      &config.Memory.CheckInterval,
-			Unit:        "minutes",
+			Jitter:      &config.Memory.Jitter,
 			Description: "How often to check memory usage",
-			Min:         1,
-			Max:         1440, // 24 hours
+			Frequent:    15 * time.Minute,
+			Infrequent:  4 * time.Hour,
 		})
 	}
 
 	// Add Disk interval if enabled
 	if config.Disk.Enabled {
-		if config.Disk.CheckInterval == 0 {
-			config.Disk.CheckInterval = 12
+		if config.Disk.CheckInterval == "" {
+			config.Disk.CheckInterval = "12h"
 		}
-		intervalNeeds = append(intervalNeeds, struct {
-			Name        string
-			Current     int
-			Default     int
-			Target      *int
-			Unit        string
-			Description string
-			Min         int
-			Max         int
-		}{
+		intervalNeeds = append(intervalNeeds, intervalNeed{
 			Name:        "Disk Check Interval",
-			Current:     config.Disk.CheckInterval,
-			Default:     12,
+			Default:     "12h",
 			Target:      &config.Disk.CheckInterval,
-			Unit:        "hours",
+			Jitter:      &config.Disk.Jitter,
 			Description: "How often to check disk usage",
-			Min:         1,
-			Max:         168, // 1 week
+			Frequent:    time.Hour,
+			Infrequent:  48 * time.Hour,
 		})
 	}
 
@@ -694,8 +1220,10 @@ func (w *ConfigurationWizard) configureIntervals(config *Config) error {
 	fmt.Println(blue("📝 Instructions:"))
 	fmt.Println("  • Use ↑/↓ arrows to navigate")
 	fmt.Println("  • Press Enter to configure selected interval")
+	fmt.Println("  • Enter durations such as 30s, 5m or 12h")
 	fmt.Println("  • Shorter intervals = more frequent checks")
-	fmt.Println("  • Recommended: CPU/Memory=60min, Disk=12hours")
+	fmt.Println("  • Jitter delays the first check randomly, so hosts started together don't check in lockstep")
+	fmt.Println("  • Recommended: CPU/Memory=1h, Disk=12h")
 	fmt.Println()
 
 	for {
@@ -704,28 +1232,22 @@ func (w *ConfigurationWizard) configureIntervals(config *Config) error {
 		var items []string
 
 		for _, interval := range intervalNeeds {
-			status := ""
-			if interval.Current > 0 {
-				status = green(fmt.Sprintf("✅ Every %d %s", interval.Current, interval.Unit))
-
-				switch interval.Unit {
-				case "minutes":
-					if interval.Current <= 5 {
-						status += red(" (Very Frequent!)")
-					} else if interval.Current <= 15 {
-						status += yellow(" (Frequent)")
-					} else if interval.Current >= 240 {
-						status += yellow(" (Infrequent)")
-					}
-				case "hours":
-					if interval.Current <= 1 {
-						status += yellow(" (Frequent)")
-					} else if interval.Current >= 48 {
-						status += yellow(" (Infrequent)")
-					}
+			status := red("❌ Not set")
+			if current, err := time.ParseDuration(*interval.Target); err == nil && current > 0 {
+				status = green(fmt.Sprintf("✅ Every %s", formatInterval(current)))
+
+				switch {
+				case current < time.Minute:
+					status += red(" (Very Frequent!)")
+				case current <= interval.Frequent:
+					status += yellow(" (Frequent)")
+				case current >= interval.Infrequent:
+					status += yellow(" (Infrequent)")
+				}
+
+				if jitter, err := time.ParseDuration(*interval.Jitter); err == nil && jitter > 0 {
+					status += fmt.Sprintf(", jitter up to %s", formatInterval(jitter))
 				}
-			} else {
-				status = red("❌ Not set")
 			}
 
 			display := fmt.Sprintf("%s - %s", interval.Name, status)
@@ -763,27 +1285,20 @@ func (w *ConfigurationWizard) configureIntervals(config *Config) error {
 		interval := &intervalNeeds[index]
 
 		fmt.Printf("\n%s\n", interval.Description)
-		fmt.Printf("Recommended: %d %s\n", interval.Default, interval.Unit)
-		fmt.Printf("Range: %d-%d %s\n\n", interval.Min, interval.Max, interval.Unit)
+		fmt.Printf("Recommended: %s\n", interval.Default)
+		fmt.Printf("Range: 5s-168h\n\n")
 
 		prompt2 := promptui.Prompt{
-			Label:   fmt.Sprintf("%s (%s)", interval.Name, interval.Unit),
-			Default: strconv.Itoa(interval.Current),
+			Label:   fmt.Sprintf("%s (e.g. 30s, 5m, 12h)", interval.Name),
+			Default: *interval.Target,
 			Validate: func(input string) error {
-				val, err := strconv.Atoi(input)
-				if err != nil {
-					return fmt.Errorf("please enter a valid number")
-				}
-				if val < interval.Min || val > interval.Max {
-					return fmt.Errorf("interval must be between %d and %d %s", interval.Min, interval.Max, interval.Unit)
+				if err := validateCheckInterval(input, ""); err != nil {
+					return err
 				}
 
 				// Warnings for extreme values
-				if interval.Unit == "minutes" && val <= 5 {
-					fmt.Printf(yellow("⚠️  Warning: %d minutes is very frequent and may impact performance\n"), val)
-				}
-				if interval.Unit == "hours" && val <= 1 {
-					fmt.Printf(yellow("⚠️  Note: %d hour is quite frequent for disk checks\n"), val)
+				if val, _ := time.ParseDuration(input); val > 0 && val < time.Minute {
+					fmt.Printf(yellow("⚠️  Warning: %s is very frequent and may impact performance\n"), formatInterval(val))
 				}
 
 				return nil
@@ -795,9 +1310,22 @@ func (w *ConfigurationWizard) configureIntervals(config *Config) error {
 			return err
 		}
 
-		newValue, _ := strconv.Atoi(result)
-		*interval.Target = newValue
-		interval.Current = newValue
+		*interval.Target = result
+
+		jitterPrompt := promptui.Prompt{
+			Label:   "Jitter before the first check (e.g. 30s, 10m; empty for none)",
+			Default: *interval.Jitter,
+			Validate: func(input string) error {
+				return validateCheckInterval(*interval.Target, strings.TrimSpace(input))
+			},
+		}
+
+		jitter, err := jitterPrompt.Run()
+		if err != nil {
+			return err
+		}
+
+		*interval.Jitter = strings.TrimSpace(jitter)
 
 		fmt.Println(green("✅ Interval configured successfully!"))
 		fmt.Println()
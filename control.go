@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ControlServer accepts commands such as alert acknowledgements from the CLI
+type ControlServer struct {
+	path      string
+	listener  net.Listener
+	escalator *Escalator
+	logger    *log.Logger
+}
+
+// getControlSocket returns the default path of the daemon control socket. It
+// depends on the user, so commands run as another user must be given the path.
+func getControlSocket() string {
+	return filepath.Join(getPIDDir(), appName+".sock")
+}
+
+// NewControlServer creates a control server listening on the given socket path.
+// The socket is private to the daemon's user unless a group is given, whose
+// members may then send commands too.
+func NewControlServer(path, group string, escalator *Escalator, logger *log.Logger) (*ControlServer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create control socket directory: %w", err)
+	}
+
+	// Remove a stale socket left behind by a previous run
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove stale control socket: %w", err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on control socket: %w", err)
+	}
+
+	mode := os.FileMode(0o600)
+	if group != "" {
+		if err := chownGroup(path, group); err != nil {
+			listener.Close()
+			return nil, err
+		}
+		mode = 0o660
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to set control socket permissions: %w", err)
+	}
+
+	return &ControlServer{
+		path:      path,
+		listener:  listener,
+		escalator: escalator,
+		logger:    logger,
+	}, nil
+}
+
+// chownGroup gives the named group ownership of path
+func chownGroup(path, group string) error {
+	g, err := user.LookupGroup(group)
+	if err != nil {
+		return fmt.Errorf("control group %q not found: %w", group, err)
+	}
+	gid, err := strconv.Atoi(g.Gid)
+	if err != nil {
+		return fmt.Errorf("invalid gid %q for group %s", g.Gid, group)
+	}
+	if err := os.Chown(path, -1, gid); err != nil {
+		return fmt.Errorf("failed to set control socket group: %w", err)
+	}
+	return nil
+}
+
+// Serve handles control connections until the server is closed
+func (cs *ControlServer) Serve() {
+	for {
+		conn, err := cs.listener.Accept()
+		if err != nil {
+			return
+		}
+		go cs.handle(conn)
+	}
+}
+
+// Close stops the control server and removes its socket
+func (cs *ControlServer) Close() {
+	cs.listener.Close()
+	if err := os.Remove(cs.path); err != nil && !os.IsNotExist(err) {
+		cs.logger.Printf("Error removing control socket: %v", err)
+	}
+}
+
+// handle processes a single control command
+func (cs *ControlServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return
+	}
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		fmt.Fprintln(conn, "error: empty command")
+		return
+	}
+
+	switch fields[0] {
+	case "ack":
+		if len(fields) != 2 {
+			fmt.Fprintln(conn, "error: usage: ack <alert-id>")
+			return
+		}
+		if err := cs.escalator.Acknowledge(fields[1]); err != nil {
+			fmt.Fprintf(conn, "error: %v\n", err)
+			return
+		}
+		fmt.Fprintln(conn, "ok")
+	default:
+		fmt.Fprintf(conn, "error: unknown command %q\n", fields[0])
+	}
+}
+
+// sendControlCommand sends a command to the daemon listening on socket and returns its reply
+func sendControlCommand(socket, command string) (string, error) {
+	conn, err := net.DialTimeout("unix", socket, 5*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to ServerHealth daemon at %s (is it running?): %w", socket, err)
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(10 * time.Second)); err != nil {
+		return "", err
+	}
+
+	if _, err := fmt.Fprintln(conn, command); err != nil {
+		return "", fmt.Errorf("failed to send command: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read reply: %w", err)
+	}
+
+	reply = strings.TrimSpace(reply)
+	if strings.HasPrefix(reply, "error: ") {
+		return "", fmt.Errorf("%s", strings.TrimPrefix(reply, "error: "))
+	}
+	return reply, nil
+}
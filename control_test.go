@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestControlServerAck(t *testing.T) {
+	group, err := user.LookupGroupId(strconv.Itoa(os.Getgid()))
+	if err != nil {
+		t.Skipf("cannot look up current group: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		group    string
+		wantMode os.FileMode
+	}{
+		{name: "private", wantMode: 0o600},
+		{name: "group", group: group.Name, wantMode: 0o660},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			escalator := newTestEscalator()
+			defer escalator.Stop()
+
+			socket := filepath.Join(t.TempDir(), "control.sock")
+			server, err := NewControlServer(socket, tt.group, escalator, log.New(io.Discard, "", 0))
+			if err != nil {
+				t.Fatalf("NewControlServer() error = %v", err)
+			}
+			go server.Serve()
+			defer server.Close()
+
+			info, err := os.Stat(socket)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if mode := info.Mode().Perm(); mode != tt.wantMode {
+				t.Errorf("socket mode = %o, want %o", mode, tt.wantMode)
+			}
+
+			if _, err := sendControlCommand(socket, "ack a1"); err == nil || !strings.Contains(err.Error(), "no pending escalation") {
+				t.Errorf("ack of unknown alert: error = %v", err)
+			}
+
+			escalator.Track(context.Background(), &NotificationMessage{Level: NotificationLevelError, AlertID: "a1"})
+			if reply, err := sendControlCommand(socket, "ack a1"); err != nil || reply != "ok" {
+				t.Errorf("ack = %q, %v; want ok", reply, err)
+			}
+
+			if _, err := sendControlCommand(socket, "bogus"); err == nil || !strings.Contains(err.Error(), "unknown command") {
+				t.Errorf("unknown command: error = %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigControlSocket(t *testing.T) {
+	config := &Config{}
+	if got := config.GetControlSocket(); got != getControlSocket() {
+		t.Errorf("default socket = %q, want %q", got, getControlSocket())
+	}
+
+	config.Control.Socket = "/run/serverhealth/control.sock"
+	if got := config.GetControlSocket(); got != config.Control.Socket {
+		t.Errorf("configured socket = %q, want %q", got, config.Control.Socket)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// checkNames returns the names of every check the configuration defines, including
+// rules, reachability checks and the anomaly and rate-of-change checks of each metric
+func (c *Config) checkNames() map[string]bool {
+	names := make(map[string]bool)
+	for metricKey, config := range c.metricConfigs() {
+		if !config.Enabled {
+			continue
+		}
+		names[metricKey] = true
+		if config.Anomaly.Enabled {
+			names[anomalyCheckKey(metricKey)] = true
+		}
+		if len(config.ChangeThresholds) > 0 {
+			names[changeCheckKey(metricKey)] = true
+		}
+	}
+	for _, rule := range c.Rules {
+		names[rule.Name] = true
+	}
+	for _, check := range c.Reachability {
+		names[check.Name] = true
+	}
+	return names
+}
+
+// metricConfigs returns the built-in metric configurations keyed by check name
+func (c *Config) metricConfigs() map[string]MonitoringConfig {
+	return map[string]MonitoringConfig{
+		"disk":   c.Disk,
+		"cpu":    c.CPU,
+		"memory": c.Memory,
+	}
+}
+
+// checkDependencies returns the parent checks of every check that declares any.
+// Derived anomaly and rate-of-change checks share their metric's dependencies.
+func (c *Config) checkDependencies() map[string][]string {
+	dependencies := make(map[string][]string)
+	for metricKey, config := range c.metricConfigs() {
+		if !config.Enabled || len(config.DependsOn) == 0 {
+			continue
+		}
+		dependencies[metricKey] = config.DependsOn
+		dependencies[anomalyCheckKey(metricKey)] = config.DependsOn
+		dependencies[changeCheckKey(metricKey)] = config.DependsOn
+	}
+	for _, rule := range c.Rules {
+		if len(rule.DependsOn) > 0 {
+			dependencies[rule.Name] = rule.DependsOn
+		}
+	}
+	for _, check := range c.Reachability {
+		if len(check.DependsOn) > 0 {
+			dependencies[check.Name] = check.DependsOn
+		}
+	}
+	return dependencies
+}
+
+// validateDependencies checks that dependencies name existing checks and contain no cycles
+func (c *Config) validateDependencies() error {
+	names := c.checkNames()
+	dependencies := c.checkDependencies()
+
+	checks := make([]string, 0, len(dependencies))
+	for check := range dependencies {
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+
+	for _, check := range checks {
+		for _, parent := range dependencies[check] {
+			if parent == check {
+				return fmt.Errorf("%s cannot depend on itself", check)
+			}
+			if !names[parent] {
+				return fmt.Errorf("%s depends on unknown check: %s", check, parent)
+			}
+		}
+	}
+
+	// Depth-first search for cycles
+	const (
+		visiting = 1
+		done     = 2
+	)
+	marks := make(map[string]int)
+	var visit func(check string, path []string) error
+	visit = func(check string, path []string) error {
+		switch marks[check] {
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(append(path, check), " → "))
+		case done:
+			return nil
+		}
+		marks[check] = visiting
+		for _, parent := range dependencies[check] {
+			if err := visit(parent, append(path, check)); err != nil {
+				return err
+			}
+		}
+		marks[check] = done
+		return nil
+	}
+	for _, check := range checks {
+		if err := visit(check, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// failingDependency returns the first parent of a check that is failing, directly
+// or through its own dependencies, or an empty string. The caller must hold m.mu.
+func (m *Monitor) failingDependency(checkKey string) string {
+	seen := make(map[string]bool)
+	var find func(check string) string
+	find = func(check string) string {
+		for _, parent := range m.dependencies[check] {
+			if seen[parent] {
+				continue
+			}
+			seen[parent] = true
+			if state, ok := m.checkStates[parent]; ok && state.Firing {
+				return parent
+			}
+			if failing := find(parent); failing != "" {
+				return failing
+			}
+		}
+		return ""
+	}
+	return find(checkKey)
+}
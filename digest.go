@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// digestMetricNames maps recorded metric series to their display names
+var digestMetricNames = map[string]string{
+	"cpu.usage":           "CPU",
+	"memory.used_percent": "Memory",
+	"disk.used_percent":   "Disk",
+}
+
+// parseClock parses a time of day in HH:MM format
+func parseClock(value string) (int, int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q (expected HH:MM)", value)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// parseWeekday parses a weekday name such as "monday"
+func parseWeekday(value string) (time.Weekday, error) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), value) {
+			return day, nil
+		}
+	}
+	return time.Sunday, fmt.Errorf("invalid weekday %q", value)
+}
+
+// digestPeriod returns the length of the period covered by a digest
+func digestPeriod(schedule string) time.Duration {
+	if schedule == "weekly" {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// nextDigestTime returns the first scheduled digest time after now
+func nextDigestTime(config DigestConfig, now time.Time) time.Time {
+	hour, minute, _ := parseClock(config.Time)
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+
+	if config.Schedule == "weekly" {
+		weekday, _ := parseWeekday(config.Weekday)
+		next = next.AddDate(0, 0, (int(weekday)-int(next.Weekday())+7)%7)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	}
+
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// runDigest sends digest reports on the configured schedule
+func (m *Monitor) runDigest() {
+	for {
+		next := nextDigestTime(m.config.Digest, time.Now())
+		m.logger.Printf("Next %s digest scheduled for %s", m.config.Digest.Schedule, next.Format("2006-01-02 15:04"))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-timer.C:
+			m.sendDigest(next.Add(-digestPeriod(m.config.Digest.Schedule)), next)
+		case <-m.ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// sendDigest renders the report for the given period and sends it
+func (m *Monitor) sendDigest(from, to time.Time) {
+	hostname, serverIP := GetServerInfo()
+	alerts := m.history.AlertsSince(from)
+	fired := 0
+	for _, alert := range alerts {
+		if !alert.Suppressed {
+			fired++
+		}
+	}
+
+	title := "Daily Health Digest"
+	if m.config.Digest.Schedule == "weekly" {
+		title = "Weekly Health Digest"
+	}
+
+	message := &NotificationMessage{
+		Type:      NotificationTypeSlack, // Will be overridden by providers
+		Level:     NotificationLevelInfo,
+		Title:     fmt.Sprintf("%s: %s", title, hostname),
+		Message:   m.renderDigest(from, to, alerts),
+		Hostname:  hostname,
+		IP:        serverIP,
+		Timestamp: time.Now(),
+		Metric:    "Health Digest",
+		Value:     fmt.Sprintf("%d alerts fired", fired),
+		Threshold: "-",
+		Check:     "digest",
+		Labels:    m.alertLabels("digest"),
+	}
+
+	if len(m.config.Digest.Providers) > 0 {
+		m.notificationManager.SendTo(m.ctx, message, m.config.Digest.Providers)
+	} else {
+		m.notificationManager.Send(m.ctx, message)
+	}
+}
+
+// renderDigest builds the text body of a digest report
+func (m *Monitor) renderDigest(from, to time.Time, alerts []AlertRecord) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Period: %s – %s\n", from.Format("2006-01-02 15:04"), to.Format("2006-01-02 15:04"))
+
+	// Metric statistics
+	b.WriteString("\nMetrics in % (min / avg / max / p95):\n")
+	for _, metric := range m.history.Metrics() {
+		stats := computeStats(m.history.Since(metric, from))
+		if stats.Count == 0 {
+			continue
+		}
+		name := digestMetricNames[metric]
+		if name == "" {
+			name = metric
+		}
+		fmt.Fprintf(&b, "• %s: %.1f / %.1f / %.1f / %.1f\n", name, stats.Min, stats.Avg, stats.Max, stats.P95)
+	}
+
+	// Alerts fired per check; inhibited alerts are counted separately
+	perCheck := make(map[string]int)
+	fired, suppressed := 0, 0
+	for _, alert := range alerts {
+		if alert.Suppressed {
+			suppressed++
+			continue
+		}
+		fired++
+		perCheck[alert.Check]++
+	}
+	fmt.Fprintf(&b, "\nAlerts fired: %d", fired)
+	if suppressed > 0 {
+		fmt.Fprintf(&b, " (%d more suppressed by inhibition rules)", suppressed)
+	}
+	b.WriteString("\n")
+	checks := make([]string, 0, len(perCheck))
+	for check := range perCheck {
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+	for _, check := range checks {
+		fmt.Fprintf(&b, "• %s: %d\n", check, perCheck[check])
+	}
+
+	// Longest incidents, including ones still in progress
+	incidents := m.history.IncidentsSince(from)
+	incidents = append(incidents, m.ongoingIncidents(to)...)
+	sort.Slice(incidents, func(i, j int) bool { return incidents[i].Duration() > incidents[j].Duration() })
+	if len(incidents) > 0 {
+		b.WriteString("\nLongest incidents:\n")
+		for i, incident := range incidents {
+			if i == 5 {
+				break
+			}
+			fmt.Fprintf(&b, "• %s: %s (started %s)\n", incident.Check,
+				incident.Duration().Round(time.Minute), incident.Start.Format("2006-01-02 15:04"))
+		}
+	}
+
+	// Disks closest to full
+	mounts, err := GetMountUsages()
+	if err != nil {
+		m.logger.Printf("Error reading mount usage for digest: %v", err)
+	}
+	sort.Slice(mounts, func(i, j int) bool { return mounts[i].UsedPercent > mounts[j].UsedPercent })
+	if len(mounts) > 0 {
+		b.WriteString("\nDisks closest to full:\n")
+		for i, mount := range mounts {
+			if i == 5 {
+				break
+			}
+			fmt.Fprintf(&b, "• %s: %.1f%%\n", mount.Mount, mount.UsedPercent)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// ongoingIncidents returns incidents for checks that are still firing
+func (m *Monitor) ongoingIncidents(now time.Time) []Incident {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var incidents []Incident
+	for check, state := range m.checkStates {
+		if state.Firing {
+			incidents = append(incidents, Incident{Check: check, AlertID: state.AlertID, Start: state.Since, End: now})
+		}
+	}
+	return incidents
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+// pendingEscalation tracks an unacknowledged alert waiting for its next escalation tier
+type pendingEscalation struct {
+	message *NotificationMessage
+	policy  EscalationPolicy
+	next    int
+	due     time.Time   // when the next tier is notified
+	timer   *time.Timer // nil once the last tier has been notified
+}
+
+// escalationState is the escalator's state saved between daemon restarts
+type escalationState struct {
+	Pending      []savedEscalation `json:"pending,omitempty"`
+	Acknowledged []string          `json:"acknowledged,omitempty"`
+}
+
+// savedEscalation is a pending escalation as saved in the state file
+type savedEscalation struct {
+	Message *NotificationMessage `json:"message"`
+	Policy  string               `json:"policy"`
+	Next    int                  `json:"next"`
+	Due     time.Time            `json:"due,omitzero"` // zero once the last tier has been notified
+}
+
+// Escalator re-sends unacknowledged error alerts to further tiers of providers
+type Escalator struct {
+	mu       sync.Mutex
+	policies []EscalationPolicy
+	pending  map[string]*pendingEscalation
+	manager  *NotificationManager
+	logger   *log.Logger
+
+	// acknowledged holds alerts acknowledged while firing, so re-sends of
+	// them are not escalated again until they resolve
+	acknowledged map[string]bool
+
+	// onChange, when set, is called after an escalation is sent or acknowledged
+	onChange func()
+}
+
+// NewEscalator creates a new escalator for the configured policies
+func NewEscalator(policies []EscalationPolicy, manager *NotificationManager, logger *log.Logger) *Escalator {
+	return &Escalator{
+		policies:     policies,
+		pending:      make(map[string]*pendingEscalation),
+		manager:      manager,
+		logger:       logger,
+		acknowledged: make(map[string]bool),
+	}
+}
+
+// SetOnChange sets the function called after an escalation is sent or
+// acknowledged, so the caller can save the escalator's state
+func (e *Escalator) SetOnChange(onChange func()) {
+	e.onChange = onChange
+}
+
+// Track starts escalation for an error alert matching one of the policies.
+// Alerts that are already being tracked or were acknowledged are left untouched.
+func (e *Escalator) Track(ctx context.Context, message *NotificationMessage) {
+	if message.Level != NotificationLevelError || message.AlertID == "" {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, ok := e.pending[message.AlertID]; ok || e.acknowledged[message.AlertID] {
+		return
+	}
+
+	for _, policy := range e.policies {
+		if len(policy.Tiers) == 0 || !policy.Match.Matches(message) {
+			continue
+		}
+
+		pending := &pendingEscalation{message: message, policy: policy}
+		delay := time.Duration(policy.Tiers[0].AfterMinutes) * time.Minute
+		pending.due = time.Now().Add(delay)
+		pending.timer = time.AfterFunc(delay, func() { e.escalate(ctx, message.AlertID) })
+		e.pending[message.AlertID] = pending
+		return
+	}
+}
+
+// escalate sends the alert to its next tier and schedules the tier after it
+func (e *Escalator) escalate(ctx context.Context, alertID string) {
+	e.mu.Lock()
+	pending, ok := e.pending[alertID]
+	if !ok {
+		e.mu.Unlock()
+		return
+	}
+
+	tier := pending.policy.Tiers[pending.next]
+	pending.next++
+	if pending.next < len(pending.policy.Tiers) {
+		delay := time.Duration(pending.policy.Tiers[pending.next].AfterMinutes-tier.AfterMinutes) * time.Minute
+		pending.due = time.Now().Add(delay)
+		pending.timer = time.AfterFunc(delay, func() { e.escalate(ctx, alertID) })
+	} else {
+		// Keep tracking the alert so re-sends don't restart its escalation
+		pending.due = time.Time{}
+		pending.timer = nil
+	}
+	e.mu.Unlock()
+
+	escalated := *pending.message
+	escalated.Title = fmt.Sprintf("[Escalated] %s", pending.message.Title)
+	escalated.Message = fmt.Sprintf("%s\nNot acknowledged after %d minutes (policy %s, tier %d)",
+		pending.message.Message, tier.AfterMinutes, pending.policy.Name, pending.next)
+	escalated.Timestamp = time.Now()
+
+	e.logger.Printf("Escalating alert %s to %v", alertID, tier.Providers)
+	e.manager.SendTo(ctx, &escalated, tier.Providers)
+	e.changed()
+}
+
+// Acknowledge stops escalation of the given alert
+func (e *Escalator) Acknowledge(alertID string) error {
+	e.mu.Lock()
+	pending, ok := e.pending[alertID]
+	if !ok {
+		e.mu.Unlock()
+		return fmt.Errorf("no pending escalation for alert %s", alertID)
+	}
+
+	stopTimer(pending.timer)
+	delete(e.pending, alertID)
+	e.acknowledged[alertID] = true
+	e.mu.Unlock()
+
+	e.logger.Printf("Alert %s acknowledged, escalation stopped", alertID)
+	e.changed()
+	return nil
+}
+
+// Resolve stops escalation of an alert that has cleared and forgets its
+// acknowledgement
+func (e *Escalator) Resolve(alertID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.acknowledged, alertID)
+
+	if pending, ok := e.pending[alertID]; ok {
+		stopTimer(pending.timer)
+		delete(e.pending, alertID)
+	}
+}
+
+// Stop cancels the timers of all pending escalations. The escalations are
+// kept so they can still be saved and resumed by Restore after a restart.
+func (e *Escalator) Stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, pending := range e.pending {
+		stopTimer(pending.timer)
+		pending.timer = nil
+	}
+}
+
+// Snapshot returns the pending escalations and acknowledgements for the state file
+func (e *Escalator) Snapshot() escalationState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var state escalationState
+	for _, pending := range e.pending {
+		state.Pending = append(state.Pending, savedEscalation{
+			Message: pending.message,
+			Policy:  pending.policy.Name,
+			Next:    pending.next,
+			Due:     pending.due,
+		})
+	}
+	for alertID := range e.acknowledged {
+		state.Acknowledged = append(state.Acknowledged, alertID)
+	}
+	sort.Slice(state.Pending, func(i, j int) bool { return state.Pending[i].Message.AlertID < state.Pending[j].Message.AlertID })
+	sort.Strings(state.Acknowledged)
+	return state
+}
+
+// Restore resumes escalations saved by Snapshot. Tiers that fell due while the
+// daemon was stopped are notified right away; escalations whose policy no
+// longer exists are dropped.
+func (e *Escalator) Restore(ctx context.Context, state escalationState) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, alertID := range state.Acknowledged {
+		e.acknowledged[alertID] = true
+	}
+
+	for _, saved := range state.Pending {
+		if saved.Message == nil || saved.Message.AlertID == "" {
+			continue
+		}
+		policy, ok := e.policy(saved.Policy)
+		if !ok {
+			e.logger.Printf("Dropping escalation of alert %s: policy %s no longer exists", saved.Message.AlertID, saved.Policy)
+			continue
+		}
+
+		alertID := saved.Message.AlertID
+		pending := &pendingEscalation{message: saved.Message, policy: policy, next: min(saved.Next, len(policy.Tiers))}
+		if pending.next < len(policy.Tiers) {
+			pending.due = saved.Due
+			pending.timer = time.AfterFunc(max(time.Until(saved.Due), 0), func() { e.escalate(ctx, alertID) })
+		}
+		e.pending[alertID] = pending
+	}
+}
+
+// policy returns the escalation policy with the given name
+func (e *Escalator) policy(name string) (EscalationPolicy, bool) {
+	for _, policy := range e.policies {
+		if policy.Name == name && len(policy.Tiers) > 0 {
+			return policy, true
+		}
+	}
+	return EscalationPolicy{}, false
+}
+
+// changed calls the change callback, if any. The caller must not hold e.mu.
+func (e *Escalator) changed() {
+	if e.onChange != nil {
+		e.onChange()
+	}
+}
+
+// stopTimer stops a pending escalation's timer, if it still has one
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
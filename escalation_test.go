@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+)
+
+func newTestEscalator() *Escalator {
+	logger := log.New(io.Discard, "", 0)
+	policies := []EscalationPolicy{{
+		Name:  "oncall",
+		Tiers: []EscalationTier{{AfterMinutes: 15, Providers: []string{"pagerduty"}}},
+	}}
+	return NewEscalator(policies, NewNotificationManager(logger), logger)
+}
+
+func TestEscalatorAcknowledgedAlertIsNotRetracked(t *testing.T) {
+	e := newTestEscalator()
+	defer e.Stop()
+
+	alert := &NotificationMessage{Level: NotificationLevelError, Title: "Disk", AlertID: "a1"}
+	e.Track(context.Background(), alert)
+	if _, ok := e.pending["a1"]; !ok {
+		t.Fatal("alert not tracked")
+	}
+
+	if err := e.Acknowledge("a1"); err != nil {
+		t.Fatalf("Acknowledge: %v", err)
+	}
+
+	// A periodic re-send while the check keeps firing
+	e.Track(context.Background(), alert)
+	if _, ok := e.pending["a1"]; ok {
+		t.Fatal("acknowledged alert is escalating again after a re-send")
+	}
+
+	// Once resolved, the same alert ID may escalate again
+	e.Resolve("a1")
+	e.Track(context.Background(), alert)
+	if _, ok := e.pending["a1"]; !ok {
+		t.Fatal("alert not tracked after being resolved")
+	}
+}
+
+func TestEscalatorExhaustedAlertIsNotRestarted(t *testing.T) {
+	e := newTestEscalator()
+	defer e.Stop()
+
+	alert := &NotificationMessage{Level: NotificationLevelError, Title: "Disk", AlertID: "a1"}
+	e.Track(context.Background(), alert)
+	e.pending["a1"].timer.Stop()
+	e.escalate(context.Background(), "a1")
+
+	e.Track(context.Background(), alert)
+	pending, ok := e.pending["a1"]
+	if !ok {
+		t.Fatal("alert forgotten after its last tier")
+	}
+	if pending.timer != nil || pending.next != 1 {
+		t.Fatalf("escalation restarted: next=%d timer=%v", pending.next, pending.timer)
+	}
+
+	if err := e.Acknowledge("a1"); err != nil {
+		t.Fatalf("Acknowledge after last tier: %v", err)
+	}
+}
+
+func TestEscalatorIgnoresNonErrors(t *testing.T) {
+	e := newTestEscalator()
+	defer e.Stop()
+
+	tests := []*NotificationMessage{
+		{Level: NotificationLevelWarning, AlertID: "w1"},
+		{Level: NotificationLevelError},
+	}
+	for _, alert := range tests {
+		e.Track(context.Background(), alert)
+	}
+	if len(e.pending) != 0 {
+		t.Fatalf("tracked %d alerts, want 0", len(e.pending))
+	}
+}
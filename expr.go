@@ -0,0 +1,443 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ruleFunctions lists the aggregation functions available in rule expressions
+var ruleFunctions = map[string]func([]Sample) float64{
+	"avg_over": func(samples []Sample) float64 { return computeStats(samples).Avg },
+	"min_over": func(samples []Sample) float64 { return computeStats(samples).Min },
+	"max_over": func(samples []Sample) float64 { return computeStats(samples).Max },
+}
+
+// SampleSource provides metric values to rule expressions
+type SampleSource interface {
+	Latest(metric string) (Sample, bool)
+	Since(metric string, since time.Time) []Sample
+}
+
+// Expr is a parsed rule expression
+type Expr interface {
+	Eval(source SampleSource, now time.Time) (float64, error)
+	String() string
+}
+
+// numberExpr is a numeric literal
+type numberExpr struct {
+	value float64
+}
+
+func (e *numberExpr) Eval(SampleSource, time.Time) (float64, error) { return e.value, nil }
+func (e *numberExpr) String() string                                { return strconv.FormatFloat(e.value, 'f', -1, 64) }
+
+// metricExpr is a reference to the latest sample of a metric
+type metricExpr struct {
+	name string
+}
+
+func (e *metricExpr) Eval(source SampleSource, _ time.Time) (float64, error) {
+	sample, ok := source.Latest(e.name)
+	if !ok {
+		return 0, fmt.Errorf("no samples for metric %s", e.name)
+	}
+	return sample.Value, nil
+}
+
+func (e *metricExpr) String() string { return e.name }
+
+// callExpr is an aggregation over a metric's recent history, e.g. avg_over(cpu.usage, 10m)
+type callExpr struct {
+	function string
+	metric   string
+	window   time.Duration
+}
+
+func (e *callExpr) Eval(source SampleSource, now time.Time) (float64, error) {
+	samples := source.Since(e.metric, now.Add(-e.window))
+	if len(samples) == 0 {
+		return 0, fmt.Errorf("no samples for metric %s in the last %s", e.metric, e.window)
+	}
+	return ruleFunctions[e.function](samples), nil
+}
+
+func (e *callExpr) String() string {
+	return fmt.Sprintf("%s(%s, %s)", e.function, e.metric, e.window)
+}
+
+// unaryExpr is a negation or logical not
+type unaryExpr struct {
+	op      string
+	operand Expr
+}
+
+func (e *unaryExpr) Eval(source SampleSource, now time.Time) (float64, error) {
+	v, err := e.operand.Eval(source, now)
+	if err != nil {
+		return 0, err
+	}
+	if e.op == "!" {
+		return boolValue(v == 0), nil
+	}
+	return -v, nil
+}
+
+func (e *unaryExpr) String() string { return e.op + e.operand.String() }
+
+// binaryExpr is an arithmetic, comparison or logical operation
+type binaryExpr struct {
+	op          string
+	left, right Expr
+}
+
+func (e *binaryExpr) Eval(source SampleSource, now time.Time) (float64, error) {
+	l, err := e.left.Eval(source, now)
+	if err != nil {
+		return 0, err
+	}
+
+	// Short-circuit logical operators
+	switch e.op {
+	case "&&":
+		if l == 0 {
+			return 0, nil
+		}
+	case "||":
+		if l != 0 {
+			return 1, nil
+		}
+	}
+
+	r, err := e.right.Eval(source, now)
+	if err != nil {
+		return 0, err
+	}
+
+	switch e.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	case ">":
+		return boolValue(l > r), nil
+	case ">=":
+		return boolValue(l >= r), nil
+	case "<":
+		return boolValue(l < r), nil
+	case "<=":
+		return boolValue(l <= r), nil
+	case "==":
+		return boolValue(math.Abs(l-r) < 1e-9), nil
+	case "!=":
+		return boolValue(math.Abs(l-r) >= 1e-9), nil
+	case "&&", "||":
+		return boolValue(r != 0), nil
+	}
+	return 0, fmt.Errorf("unknown operator %s", e.op)
+}
+
+func (e *binaryExpr) String() string {
+	return fmt.Sprintf("(%s %s %s)", e.left, e.op, e.right)
+}
+
+// boolValue converts a boolean to the 1/0 representation used by expressions
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// exprMetrics returns the names of all metrics referenced by an expression
+func exprMetrics(expr Expr) []string {
+	var names []string
+	seen := make(map[string]bool)
+	var walk func(Expr)
+	walk = func(e Expr) {
+		switch e := e.(type) {
+		case *metricExpr:
+			if !seen[e.name] {
+				seen[e.name] = true
+				names = append(names, e.name)
+			}
+		case *callExpr:
+			if !seen[e.metric] {
+				seen[e.metric] = true
+				names = append(names, e.metric)
+			}
+		case *unaryExpr:
+			walk(e.operand)
+		case *binaryExpr:
+			walk(e.left)
+			walk(e.right)
+		}
+	}
+	walk(expr)
+	return names
+}
+
+// token kinds produced by the expression lexer
+const (
+	tokenEOF = iota
+	tokenNumber
+	tokenDuration
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+// token is a lexical element of a rule expression
+type token struct {
+	kind int
+	text string
+	pos  int
+}
+
+// tokenize splits a rule expression into tokens
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		c := rune(input[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case unicode.IsDigit(c) || c == '.':
+			start := i
+			for i < len(input) && (unicode.IsDigit(rune(input[i])) || input[i] == '.') {
+				i++
+			}
+			// A number directly followed by a unit is a duration such as 10m
+			if i < len(input) && strings.ContainsRune("smhd", rune(input[i])) &&
+				(i+1 == len(input) || !isIdentRune(rune(input[i+1]))) {
+				i++
+				tokens = append(tokens, token{kind: tokenDuration, text: input[start:i], pos: start})
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: input[start:i], pos: start})
+		case unicode.IsLetter(c) || c == '_':
+			start := i
+			for i < len(input) && (isIdentRune(rune(input[i])) || input[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: input[start:i], pos: start})
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", ">=", "<=", "==", "!=", ">", "<", "!", "+", "-", "*", "/"} {
+				if strings.HasPrefix(input[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i+1)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// isIdentRune reports whether r may appear in an identifier
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// parseDuration parses durations such as 30s, 10m, 2h and 1d
+func parseDuration(text string) (time.Duration, error) {
+	if strings.HasSuffix(text, "d") {
+		days, err := strconv.ParseFloat(strings.TrimSuffix(text, "d"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", text)
+		}
+		return time.Duration(days * float64(24*time.Hour)), nil
+	}
+	d, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", text)
+	}
+	return d, nil
+}
+
+// exprParser is a recursive descent parser for rule expressions
+type exprParser struct {
+	tokens []token
+	pos    int
+}
+
+// ParseExpr parses a rule expression such as
+// "memory.used_percent > 90 && avg_over(cpu.usage, 10m) > 80"
+func ParseExpr(input string) (Expr, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &exprParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+	return expr, nil
+}
+
+func (p *exprParser) peek() token { return p.tokens[p.pos] }
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseBinary parses a left-associative chain of the given operators
+func (p *exprParser) parseBinary(ops []string, operand func() (Expr, error)) (Expr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokenOperator || !containsString(ops, tok.text) {
+			return left, nil
+		}
+		p.next()
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: tok.text, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseOr() (Expr, error) {
+	return p.parseBinary([]string{"||"}, p.parseAnd)
+}
+
+func (p *exprParser) parseAnd() (Expr, error) {
+	return p.parseBinary([]string{"&&"}, p.parseComparison)
+}
+
+func (p *exprParser) parseComparison() (Expr, error) {
+	return p.parseBinary([]string{">", ">=", "<", "<=", "==", "!="}, p.parseSum)
+}
+
+func (p *exprParser) parseSum() (Expr, error) {
+	return p.parseBinary([]string{"+", "-"}, p.parseProduct)
+}
+
+func (p *exprParser) parseProduct() (Expr, error) {
+	return p.parseBinary([]string{"*", "/"}, p.parseUnary)
+}
+
+func (p *exprParser) parseUnary() (Expr, error) {
+	if tok := p.peek(); tok.kind == tokenOperator && (tok.text == "!" || tok.text == "-") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{op: tok.text, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (Expr, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos+1)
+		}
+		return &numberExpr{value: value}, nil
+	case tokenIdent:
+		if p.peek().kind == tokenLParen {
+			return p.parseCall(tok)
+		}
+		return &metricExpr{name: tok.text}, nil
+	case tokenLParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("expected ) at position %d", closing.pos+1)
+		}
+		return expr, nil
+	case tokenEOF:
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+}
+
+// parseCall parses function(metric, duration)
+func (p *exprParser) parseCall(name token) (Expr, error) {
+	if _, ok := ruleFunctions[name.text]; !ok {
+		return nil, fmt.Errorf("unknown function %s at position %d", name.text, name.pos+1)
+	}
+	p.next() // (
+
+	metric := p.next()
+	if metric.kind != tokenIdent {
+		return nil, fmt.Errorf("%s: expected metric name at position %d", name.text, metric.pos+1)
+	}
+	if comma := p.next(); comma.kind != tokenComma {
+		return nil, fmt.Errorf("%s: expected , at position %d", name.text, comma.pos+1)
+	}
+	window := p.next()
+	if window.kind != tokenDuration {
+		return nil, fmt.Errorf("%s: expected duration such as 10m at position %d", name.text, window.pos+1)
+	}
+	duration, err := parseDuration(window.text)
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("%s: window must be positive at position %d", name.text, window.pos+1)
+	}
+	if closing := p.next(); closing.kind != tokenRParen {
+		return nil, fmt.Errorf("%s: expected ) at position %d", name.text, closing.pos+1)
+	}
+
+	return &callExpr{function: name.text, metric: metric.text, window: duration}, nil
+}
+
+// containsString reports whether values contains s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
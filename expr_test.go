@@ -0,0 +1,201 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSource is a SampleSource backed by fixed samples, oldest first
+type fakeSource map[string][]Sample
+
+func (s fakeSource) Latest(metric string) (Sample, bool) {
+	samples := s[metric]
+	if len(samples) == 0 {
+		return Sample{}, false
+	}
+	return samples[len(samples)-1], true
+}
+
+func (s fakeSource) Since(metric string, since time.Time) []Sample {
+	var recent []Sample
+	for _, sample := range s[metric] {
+		if !sample.Time.Before(since) {
+			recent = append(recent, sample)
+		}
+	}
+	return recent
+}
+
+func TestParseExpr(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"cpu.usage > 90", "(cpu.usage > 90)"},
+		{"1 + 2 * 3", "(1 + (2 * 3))"},
+		{"(1 + 2) * 3", "((1 + 2) * 3)"},
+		{"a > 1 && b > 2 || c > 3", "(((a > 1) && (b > 2)) || (c > 3))"},
+		{"!(a > 1)", "!(a > 1)"},
+		{"-a + 1", "(-a + 1)"},
+		{"avg_over(cpu.usage, 10m) >= 80", "(avg_over(cpu.usage, 10m0s) >= 80)"},
+		{"max_over(memory.used_percent, 1d) < 50.5", "(max_over(memory.used_percent, 24h0m0s) < 50.5)"},
+		{"min_over(disk.used_percent, 1.5h) != 0", "(min_over(disk.used_percent, 1h30m0s) != 0)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := ParseExpr(tt.input)
+			if err != nil {
+				t.Fatalf("ParseExpr() error = %v", err)
+			}
+			if got := expr.String(); got != tt.want {
+				t.Errorf("ParseExpr() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExprErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"", "unexpected end of expression"},
+		{"cpu.usage >", "unexpected end of expression"},
+		{"(cpu.usage > 1", "expected )"},
+		{"cpu.usage > 1)", `unexpected ")"`},
+		{"cpu.usage $ 1", "unexpected character"},
+		{"sum_over(cpu.usage, 10m)", "unknown function sum_over"},
+		{"avg_over(10, 10m)", "expected metric name"},
+		{"avg_over(cpu.usage 10m)", "expected ,"},
+		{"avg_over(cpu.usage, 10)", "expected duration"},
+		{"avg_over(cpu.usage, 10m", "expected )"},
+		{"avg_over(cpu.usage, 0s)", "window must be positive"},
+		{"avg_over(cpu.usage, 0d)", "window must be positive"},
+		{"avg_over(cpu.usage, 1.2.3m)", "invalid duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := ParseExpr(tt.input)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseExpr(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExprEval(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	source := fakeSource{
+		"cpu.usage": {
+			{Time: now.Add(-20 * time.Minute), Value: 10},
+			{Time: now.Add(-8 * time.Minute), Value: 70},
+			{Time: now.Add(-4 * time.Minute), Value: 90},
+			{Time: now, Value: 95},
+		},
+		"memory.used_percent": {{Time: now, Value: 60}},
+	}
+
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr string
+	}{
+		{input: "cpu.usage", want: 95},
+		{input: "cpu.usage - memory.used_percent", want: 35},
+		{input: "memory.used_percent / 4 + 1", want: 16},
+		{input: "-memory.used_percent", want: -60},
+		{input: "avg_over(cpu.usage, 10m)", want: 85},
+		{input: "min_over(cpu.usage, 10m)", want: 70},
+		{input: "max_over(cpu.usage, 1h)", want: 95},
+		{input: "cpu.usage > 90 && memory.used_percent > 50", want: 1},
+		{input: "cpu.usage > 99 || memory.used_percent < 50", want: 0},
+		{input: "!(cpu.usage > 99)", want: 1},
+		{input: "0.1 + 0.2 == 0.3", want: 1},
+		{input: "cpu.usage != 95", want: 0},
+		// Logical operators short-circuit, so a missing metric is not evaluated
+		{input: "cpu.usage < 0 && disk.used_percent > 1", want: 0},
+		{input: "cpu.usage > 0 || disk.used_percent > 1", want: 1},
+		{input: "disk.used_percent > 1", wantErr: "no samples for metric disk.used_percent"},
+		{input: "avg_over(memory.used_percent, 1m) > 1", want: 1},
+		{input: "avg_over(cpu.usage, 1s) + avg_over(disk.used_percent, 1h)", wantErr: "in the last 1h0m0s"},
+		{input: "cpu.usage / (memory.used_percent - 60)", wantErr: "division by zero"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			expr, err := ParseExpr(tt.input)
+			if err != nil {
+				t.Fatalf("ParseExpr() error = %v", err)
+			}
+			got, err := expr.Eval(source, now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Eval() = %v, %v; want error %q", got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Eval() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExprMetrics(t *testing.T) {
+	expr, err := ParseExpr("cpu.usage > 1 && avg_over(memory.used_percent, 5m) > cpu.usage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Join(exprMetrics(expr), ",")
+	if got != "cpu.usage,memory.used_percent" {
+		t.Errorf("exprMetrics() = %s", got)
+	}
+}
+
+func TestConfigRejectsZeroWindow(t *testing.T) {
+	err := validateRule(&RuleConfig{Name: "busy", Expr: "avg_over(cpu.usage, 0s) > 80"})
+	if err == nil || !strings.Contains(err.Error(), "window must be positive") {
+		t.Fatalf("validateRule() error = %v", err)
+	}
+}
+
+func TestValidateRuleReservedNames(t *testing.T) {
+	for _, name := range []string{"disk", "cpu-anomaly", "memory-change"} {
+		t.Run(name, func(t *testing.T) {
+			err := validateRule(&RuleConfig{Name: name, Expr: "cpu.usage > 80"})
+			if err == nil || !strings.Contains(err.Error(), "reserved for a built-in check") {
+				t.Fatalf("validateRule() error = %v", err)
+			}
+		})
+	}
+	if err := validateRule(&RuleConfig{Name: "cpu-busy", Expr: "cpu.usage > 80"}); err != nil {
+		t.Errorf("validateRule() error = %v", err)
+	}
+}
+
+func TestRuleNotSentAgainWithinHalfInterval(t *testing.T) {
+	provider := &recordingProvider{}
+	m := newTestMonitor(t, provider)
+	m.statePath = filepath.Join(t.TempDir(), stateFileName)
+	m.history.Record("cpu.usage", 95, time.Now())
+
+	rules, err := compileRules([]RuleConfig{{Name: "busy", Expr: "cpu.usage > 90"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.evaluateRule(rules[0], time.Minute)
+	m.evaluateRule(rules[0], time.Minute)
+
+	if !m.notificationManager.Wait(5 * time.Second) {
+		t.Fatal("notifications did not finish")
+	}
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.sent) != 1 {
+		t.Errorf("sent %d alerts, want 1", len(provider.sent))
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// alertGroup collects alerts for one provider and host raised within the grouping window
+type alertGroup struct {
+	ctx      context.Context
+	provider namedProvider
+	messages []*NotificationMessage
+}
+
+// SetGroupWindow enables batching of alerts for the same host raised within the window.
+// A zero window sends every alert individually.
+func (nm *NotificationManager) SetGroupWindow(window time.Duration) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	nm.groupWindow = window
+}
+
+// isAlert reports whether a message is an alert raised by a check, as opposed to
+// informational messages such as digests, shutdown and rate limit notices
+func isAlert(message *NotificationMessage) bool {
+	return (message.AlertID != "" || len(message.Group) > 0) && message.Level != NotificationLevelInfo
+}
+
+// isGroupable reports whether a message is an alert that may be batched with others.
+// Informational messages such as digests and shutdown notices are always sent immediately.
+func isGroupable(message *NotificationMessage) bool {
+	return isAlert(message) && len(message.Group) == 0
+}
+
+// messageFingerprint identifies messages with identical content
+func messageFingerprint(message *NotificationMessage) string {
+	return strings.Join([]string{
+		message.Hostname, message.Check, message.AlertID, string(message.Level),
+		message.Title, message.Message, message.Value, message.Threshold,
+	}, "\x00")
+}
+
+// enqueue adds a message to the pending group for its provider and host,
+// dropping it if an identical message is already waiting
+func (nm *NotificationManager) enqueue(ctx context.Context, provider namedProvider, message *NotificationMessage) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	key := provider.name + "\x00" + message.Hostname
+	group, ok := nm.groups[key]
+	if !ok {
+		group = &alertGroup{ctx: ctx, provider: provider}
+		nm.groups[key] = group
+		time.AfterFunc(nm.groupWindow, func() { nm.flushGroup(key) })
+	}
+
+	fingerprint := messageFingerprint(message)
+	for _, pending := range group.messages {
+		if messageFingerprint(pending) == fingerprint {
+			nm.logger.Printf("Dropping duplicate notification %q for %s", message.Title, provider.name)
+			return
+		}
+	}
+
+	group.messages = append(group.messages, message)
+}
+
+// flushGroup sends a pending group, combining its alerts into one notification
+func (nm *NotificationManager) flushGroup(key string) {
+	nm.mu.Lock()
+	group, ok := nm.groups[key]
+	delete(nm.groups, key)
+	nm.mu.Unlock()
+
+	if !ok || len(group.messages) == 0 {
+		return
+	}
+
+	if len(group.messages) == 1 {
+		nm.deliver(group.ctx, group.provider, group.messages[0])
+		return
+	}
+
+	nm.deliver(group.ctx, group.provider, newGroupedMessage(group.messages))
+}
+
+// Flush immediately sends every pending group
+func (nm *NotificationManager) Flush() {
+	nm.mu.Lock()
+	keys := make([]string, 0, len(nm.groups))
+	for key := range nm.groups {
+		keys = append(keys, key)
+	}
+	nm.mu.Unlock()
+
+	for _, key := range keys {
+		nm.flushGroup(key)
+	}
+}
+
+// newGroupedMessage combines several alerts for the same host into a single message
+func newGroupedMessage(messages []*NotificationMessage) *NotificationMessage {
+	first := messages[0]
+	level := NotificationLevelWarning
+	checks := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if message.Level == NotificationLevelError {
+			level = NotificationLevelError
+		}
+		checks = append(checks, message.Metric)
+	}
+
+	return &NotificationMessage{
+		Type:      first.Type,
+		Level:     level,
+		Title:     fmt.Sprintf("%d alerts on %s", len(messages), first.Hostname),
+		Message:   fmt.Sprintf("Firing checks: %s", strings.Join(checks, ", ")),
+		Hostname:  first.Hostname,
+		IP:        first.IP,
+		Timestamp: time.Now(),
+		Metric:    strings.Join(checks, ", "),
+		Value:     fmt.Sprintf("%d alerts", len(messages)),
+		Threshold: "-",
+		Group:     messages,
+	}
+}
+
+// groupLines renders one line per grouped alert using the given bold markup
+func groupLines(message *NotificationMessage, bold func(string) string) string {
+	var b strings.Builder
+	for _, alert := range message.Group {
+		fmt.Fprintf(&b, "• %s %s: %s %s (threshold %s)", levelEmoji(alert.Level), bold(alert.Title),
+			alert.Metric, alert.Value, alert.Threshold)
+		if alert.AlertID != "" {
+			fmt.Fprintf(&b, " [alert %s]", alert.AlertID)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// levelEmoji returns the emoji used for a notification level
+func levelEmoji(level NotificationLevel) string {
+	switch level {
+	case NotificationLevelWarning:
+		return "⚠️"
+	case NotificationLevelError:
+		return "❌"
+	default:
+		return "ℹ️"
+	}
+}
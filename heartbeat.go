@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// heartbeatStatus is the body sent with every heartbeat ping
+type heartbeatStatus struct {
+	Hostname      string    `json:"hostname"`
+	IP            string    `json:"ip"`
+	Version       string    `json:"version"`
+	Timestamp     time.Time `json:"timestamp"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+	FiringChecks  []string  `json:"firing_checks"`
+	UnknownChecks []string  `json:"unknown_checks"`
+}
+
+// runHeartbeat pings the heartbeat URL on the configured interval
+func (m *Monitor) runHeartbeat() {
+	ticker := time.NewTicker(parseInterval(m.config.Heartbeat.Interval))
+	defer ticker.Stop()
+
+	// Initial ping
+	m.sendHeartbeat()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.sendHeartbeat()
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// sendHeartbeat pings the heartbeat URL once, logging any failure.
+// Missed pings are not retried; the next interval sends a fresh one.
+func (m *Monitor) sendHeartbeat() {
+	hostname, serverIP := GetServerInfo()
+	status := heartbeatStatus{
+		Hostname:      hostname,
+		IP:            serverIP,
+		Version:       version,
+		Timestamp:     time.Now(),
+		UptimeSeconds: int64(time.Since(m.startedAt).Seconds()),
+		FiringChecks:  m.firingChecks(),
+		UnknownChecks: m.unknownChecks(),
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, 30*time.Second)
+	defer cancel()
+
+	if err := pingHeartbeat(ctx, m.notificationManager.client, m.config.Heartbeat, status); err != nil {
+		m.logger.Printf("Heartbeat failed: %v", err)
+	}
+}
+
+// pingHeartbeat sends a single heartbeat request
+func pingHeartbeat(ctx context.Context, client *http.Client, config HeartbeatConfig, status heartbeatStatus) error {
+	var req *http.Request
+	var err error
+
+	if config.Method == http.MethodGet {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, config.URL, nil)
+	} else {
+		body, marshalErr := json.Marshal(status)
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", marshalErr)
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, config.URL, bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "ServerHealth/1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// firingChecks returns the names of checks that are currently firing, sorted.
+// Checks in the UNKNOWN state are not included.
+func (m *Monitor) firingChecks() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	checks := make([]string, 0)
+	for check, state := range m.checkStates {
+		if state.Firing && !state.Unknown {
+			checks = append(checks, check)
+		}
+	}
+	sort.Strings(checks)
+	return checks
+}
+
+// unknownChecks returns the names of checks whose dependencies are failing, sorted
+func (m *Monitor) unknownChecks() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	checks := make([]string, 0)
+	for check, state := range m.checkStates {
+		if state.Unknown {
+			checks = append(checks, check)
+		}
+	}
+	sort.Strings(checks)
+	return checks
+}
+
+// NotifyShutdown sends a "ServerHealth stopped" message and waits for it to be delivered
+func (m *Monitor) NotifyShutdown(timeout time.Duration) {
+	if !m.config.Heartbeat.NotifyOnShutdown {
+		return
+	}
+
+	hostname, serverIP := GetServerInfo()
+	message := &NotificationMessage{
+		Type:      NotificationTypeSlack, // Will be overridden by providers
+		Level:     NotificationLevelWarning,
+		Title:     "ServerHealth stopped",
+		Message:   fmt.Sprintf("The ServerHealth daemon on %s was shut down. Health checks are no longer running.", hostname),
+		Hostname:  hostname,
+		IP:        serverIP,
+		Timestamp: time.Now(),
+		Metric:    "ServerHealth",
+		Value:     "stopped",
+		Threshold: "-",
+		Check:     "serverhealth",
+		Labels:    m.alertLabels("serverhealth"),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Send alerts still waiting in a grouping window before the shutdown notice
+	m.notificationManager.Flush()
+	m.notificationManager.Send(ctx, message)
+	if !m.notificationManager.Wait(timeout) {
+		m.logger.Println("Timed out waiting for shutdown notification to be delivered")
+	}
+}
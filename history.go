@@ -0,0 +1,205 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// historyRetention is how long samples and alert records are kept in memory
+const historyRetention = 8 * 24 * time.Hour
+
+// Sample represents a single metric observation
+type Sample struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// AlertRecord represents an alert raised by a check
+type AlertRecord struct {
+	Time         time.Time         `json:"time"`
+	Check        string            `json:"check"`
+	Level        NotificationLevel `json:"level"`
+	AlertID      string            `json:"alert_id"`
+	Value        string            `json:"value,omitempty"`
+	Suppressed   bool              `json:"suppressed,omitempty"`
+	SuppressedBy string            `json:"suppressed_by,omitempty"`
+}
+
+// Incident represents a period during which a check was firing
+type Incident struct {
+	Check   string    `json:"check"`
+	AlertID string    `json:"alert_id"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// Duration returns how long the incident lasted
+func (i Incident) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// MetricHistory keeps recent metric samples, alerts and incidents in memory
+type MetricHistory struct {
+	mu        sync.Mutex
+	retention time.Duration
+	samples   map[string][]Sample
+	alerts    []AlertRecord
+	incidents []Incident
+}
+
+// NewMetricHistory creates a new history that keeps records for the given duration
+func NewMetricHistory(retention time.Duration) *MetricHistory {
+	return &MetricHistory{
+		retention: retention,
+		samples:   make(map[string][]Sample),
+	}
+}
+
+// Record adds a sample for the named metric
+func (h *MetricHistory) Record(metric string, value float64, at time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := at.Add(-h.retention)
+	samples := append(h.samples[metric], Sample{Time: at, Value: value})
+	for len(samples) > 0 && samples[0].Time.Before(cutoff) {
+		samples = samples[1:]
+	}
+	h.samples[metric] = samples
+}
+
+// Since returns the samples of the named metric recorded at or after since
+func (h *MetricHistory) Since(metric string, since time.Time) []Sample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := h.samples[metric]
+	i := sort.Search(len(samples), func(i int) bool { return !samples[i].Time.Before(since) })
+	return append([]Sample(nil), samples[i:]...)
+}
+
+// Latest returns the most recent sample of the named metric
+func (h *MetricHistory) Latest(metric string) (Sample, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	samples := h.samples[metric]
+	if len(samples) == 0 {
+		return Sample{}, false
+	}
+	return samples[len(samples)-1], true
+}
+
+// Change returns how much the named metric changed between the oldest sample
+// recorded at or after since and the most recent one. It reports false when
+// fewer than two samples fall in that period.
+func (h *MetricHistory) Change(metric string, since time.Time) (float64, time.Duration, bool) {
+	samples := h.Since(metric, since)
+	if len(samples) < 2 {
+		return 0, 0, false
+	}
+	first, last := samples[0], samples[len(samples)-1]
+	return last.Value - first.Value, last.Time.Sub(first.Time), true
+}
+
+// Metrics returns the names of all metrics with recorded samples, sorted
+func (h *MetricHistory) Metrics() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	names := make([]string, 0, len(h.samples))
+	for name := range h.samples {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RecordAlert adds an alert record
+func (h *MetricHistory) RecordAlert(record AlertRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.alerts = append(h.alerts, record)
+	cutoff := record.Time.Add(-h.retention)
+	for len(h.alerts) > 0 && h.alerts[0].Time.Before(cutoff) {
+		h.alerts = h.alerts[1:]
+	}
+}
+
+// AlertsSince returns the alerts recorded at or after since
+func (h *MetricHistory) AlertsSince(since time.Time) []AlertRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var records []AlertRecord
+	for _, record := range h.alerts {
+		if !record.Time.Before(since) {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// RecordIncident adds a finished incident
+func (h *MetricHistory) RecordIncident(incident Incident) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.incidents = append(h.incidents, incident)
+	cutoff := incident.End.Add(-h.retention)
+	for len(h.incidents) > 0 && h.incidents[0].End.Before(cutoff) {
+		h.incidents = h.incidents[1:]
+	}
+}
+
+// IncidentsSince returns the incidents that ended at or after since
+func (h *MetricHistory) IncidentsSince(since time.Time) []Incident {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var incidents []Incident
+	for _, incident := range h.incidents {
+		if !incident.End.Before(since) {
+			incidents = append(incidents, incident)
+		}
+	}
+	return incidents
+}
+
+// SampleStats summarises a set of samples
+type SampleStats struct {
+	Count int
+	Min   float64
+	Avg   float64
+	Max   float64
+	P95   float64
+}
+
+// computeStats returns min, average, max and 95th percentile of the samples
+func computeStats(samples []Sample) SampleStats {
+	if len(samples) == 0 {
+		return SampleStats{}
+	}
+
+	values := make([]float64, len(samples))
+	var sum float64
+	for i, s := range samples {
+		values[i] = s.Value
+		sum += s.Value
+	}
+	sort.Float64s(values)
+
+	// Nearest-rank percentile
+	rank := int(math.Ceil(0.95*float64(len(values)))) - 1
+
+	return SampleStats{
+		Count: len(values),
+		Min:   values[0],
+		Avg:   sum / float64(len(values)),
+		Max:   values[len(values)-1],
+		P95:   values[rank],
+	}
+}
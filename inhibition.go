@@ -0,0 +1,22 @@
+package main
+
+// inhibitedBy returns the check whose firing alert inhibits the message, or an
+// empty string if the message is not inhibited. The caller must hold m.mu.
+func (m *Monitor) inhibitedBy(message *NotificationMessage) string {
+	for _, rule := range m.config.InhibitRules {
+		if !rule.Target.Matches(message) {
+			continue
+		}
+
+		for check, state := range m.checkStates {
+			if check == message.Check || !state.Firing || state.Last == nil {
+				continue
+			}
+			if rule.Source.Matches(state.Last) {
+				return check
+			}
+		}
+	}
+
+	return ""
+}
@@ -38,6 +38,7 @@ func main() {
 		NewInstallCmd(),
 		NewUninstallCmd(),
 		NewLogsCmd(),
+		NewAckCmd(),
 		NewDaemonCmd(),
 	)
 
@@ -2,11 +2,28 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log"
+	mathrand "math/rand/v2"
+	"path/filepath"
+	"sync"
 	"time"
 )
 
+// checkState tracks whether a check is currently firing and the alert it raised
+type checkState struct {
+	Firing      bool                 `json:"firing"`
+	AlertID     string               `json:"alert_id,omitempty"`
+	Since       time.Time            `json:"since,omitempty"`
+	Transitions []time.Time          `json:"transitions,omitempty"`
+	Flapping    bool                 `json:"flapping,omitempty"`
+	Unknown     bool                 `json:"unknown,omitempty"`
+	Last        *NotificationMessage `json:"last,omitempty"`
+	LastSent    time.Time            `json:"last_sent,omitempty"`
+}
+
 // Monitor represents the monitoring system
 type Monitor struct {
 	config              *Config
@@ -14,8 +31,17 @@ type Monitor struct {
 	ctx                 context.Context
 	cancel              context.CancelFunc
 	notificationManager *NotificationManager
-	notificationCounts  map[string]int
-	lastResetDate       string
+	escalator           *Escalator
+	history             *MetricHistory
+	startedAt           time.Time
+	mu                  sync.Mutex
+	checkStates         map[string]*checkState
+	limiters            map[string]*SlidingWindowLimiter
+	baselines           map[string]*Baseline
+	dependencies        map[string][]string
+	statePath           string
+	stateChanged        bool
+	stateSavedAt        time.Time
 }
 
 // NewMonitor creates a new monitor instance
@@ -23,6 +49,10 @@ func NewMonitor(config *Config, logger *log.Logger) *Monitor {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	notificationManager := NewNotificationManager(logger)
+	notificationManager.SetRouter(NewRouter(config.Routes))
+	if config.Grouping.Enabled {
+		notificationManager.SetGroupWindow(parseInterval(config.Grouping.Window))
+	}
 
 	// Add notification providers based on configuration
 	for _, notification := range config.GetEnabledNotifications() {
@@ -30,29 +60,80 @@ func NewMonitor(config *Config, logger *log.Logger) *Monitor {
 
 		switch notification.Type {
 		case string(NotificationTypeSlack):
-			provider = NewSlackProvider(notification.WebhookURL, notificationManager.client)
+			provider = NewSlackProvider(notification.WebhookURL, notification.Template, notificationManager.client)
 		case string(NotificationTypeTelegram):
-			provider = NewTelegramProvider(notification.BotToken, notification.ChatID, notificationManager.client)
+			provider = NewTelegramProvider(notification.BotToken, notification.ChatID, notification.Template, notificationManager.client)
 		case string(NotificationTypeDiscord):
-			provider = NewDiscordProvider(notification.WebhookURL, notificationManager.client)
+			provider = NewDiscordProvider(notification.WebhookURL, notification.Template, notificationManager.client)
+		case string(NotificationTypeTeams):
+			provider = NewTeamsProvider(notification.WebhookURL, notification.Template, notificationManager.client)
+		case string(NotificationTypeGoogleChat):
+			provider = NewGoogleChatProvider(notification.WebhookURL, notification.Template, notificationManager.client)
+		case string(NotificationTypeWebhook):
+			provider = NewWebhookProvider(notification.WebhookURL, notification.Method, notification.Headers,
+				notification.Payload, notificationManager.client)
+		case string(NotificationTypeEmail):
+			provider = NewEmailProvider(notification.Host, notification.Port, notification.From, notification.To,
+				notification.Username, notification.Password, notification.TLS, notification.Auth, notification.CAFile, notification.Template)
+		case string(NotificationTypePagerDuty):
+			provider = NewPagerDutyProvider(notification.RoutingKey, notification.URL, notificationManager.client)
+		case string(NotificationTypeOpsgenie):
+			provider = NewOpsgenieProvider(notification.APIKey, notification.Region, notification.URL, notificationManager.client)
+		case string(NotificationTypeNtfy):
+			provider = NewNtfyProvider(notification.URL, notification.Token, notification.Template, notificationManager.client)
+		case string(NotificationTypeGotify):
+			provider = NewGotifyProvider(notification.URL, notification.Token, notification.Template, notificationManager.client)
+		case string(NotificationTypeCommand):
+			command := NewCommandProvider(notification.ProviderName(), notification.Command, notification.Args,
+				time.Duration(notification.TimeoutSeconds)*time.Second, notification.MaxConcurrent, logger)
+			if notification.ReportResult {
+				command.SetReporter(notificationManager.SendFollowUp)
+			}
+			provider = command
+		case string(NotificationTypeSyslog):
+			provider = NewSyslogProvider(notification.Network, notification.Address, notification.Facility, notification.CAFile,
+				notification.Template)
+		case string(NotificationTypeJournald):
+			provider = NewJournaldProvider(notification.Address, notification.Fields, notification.Template)
+		case string(NotificationTypeMatrix):
+			provider = NewMatrixProvider(notification.URL, notification.Token, notification.RoomID, notification.Template,
+				notificationManager.client)
+		case string(NotificationTypePushover):
+			provider = NewPushoverProvider(notification.Token, notification.UserKey, notification.RetrySeconds,
+				notification.ExpireSeconds, notification.URL, notification.Template, notificationManager.client)
 		}
 
 		if provider != nil {
-			if err := notificationManager.AddProvider(provider); err != nil {
+			if err := notificationManager.AddProvider(notification.ProviderName(), provider, notification.RateLimits...); err != nil {
 				logger.Printf("Failed to add notification provider %s: %v", notification.Type, err)
 			}
 		}
 	}
 
-	return &Monitor{
+	monitor := &Monitor{
 		config:              config,
 		logger:              logger,
 		ctx:                 ctx,
 		cancel:              cancel,
 		notificationManager: notificationManager,
-		notificationCounts:  make(map[string]int),
-		lastResetDate:       time.Now().Format("2006-01-02"),
+		escalator:           NewEscalator(config.Escalations, notificationManager, logger),
+		history:             NewMetricHistory(historyRetention),
+		startedAt:           time.Now(),
+		checkStates:         make(map[string]*checkState),
+		limiters:            make(map[string]*SlidingWindowLimiter),
+		baselines:           make(map[string]*Baseline),
+		dependencies:        config.checkDependencies(),
+		statePath:           filepath.Join(config.GetDataDir(), stateFileName),
+	}
+
+	monitor.escalator.SetOnChange(monitor.escalationChanged)
+
+	// Restore alert state and counters from the previous run
+	if err := monitor.loadState(); err != nil {
+		logger.Printf("Failed to load saved state, starting fresh: %v", err)
 	}
+
+	return monitor
 }
 
 // Start begins the monitoring process
@@ -61,6 +142,15 @@ func (m *Monitor) Start() {
 
 	hostname, serverIP := GetServerInfo()
 
+	// Accept acknowledgements from the CLI
+	controlServer, err := NewControlServer(m.config.GetControlSocket(), m.config.Control.Group, m.escalator, m.logger)
+	if err != nil {
+		m.logger.Printf("Control socket unavailable, alerts cannot be acknowledged: %v", err)
+	} else {
+		go controlServer.Serve()
+		defer controlServer.Close()
+	}
+
 	// Start monitoring goroutines
 	if m.config.Disk.Enabled {
 		go m.monitorDiskUsage(hostname, serverIP)
@@ -74,6 +164,22 @@ func (m *Monitor) Start() {
 		go m.monitorMemoryUsage(hostname, serverIP)
 	}
 
+	if len(m.config.Rules) > 0 {
+		go m.runRules()
+	}
+
+	for _, check := range m.config.Reachability {
+		go m.monitorReachability(check)
+	}
+
+	if m.config.Digest.Enabled {
+		go m.runDigest()
+	}
+
+	if m.config.Heartbeat.Enabled {
+		go m.runHeartbeat()
+	}
+
 	// Keep the main goroutine alive
 	<-m.ctx.Done()
 }
@@ -81,17 +187,23 @@ func (m *Monitor) Start() {
 // Stop stops the monitoring process
 func (m *Monitor) Stop() {
 	m.logger.Println("Stopping ServerHealth monitoring...")
+	m.escalator.Stop()
+
+	// Pending escalations are saved and resumed on the next start
+	m.mu.Lock()
+	m.saveState()
+	m.mu.Unlock()
+
 	m.cancel()
 }
 
-// resetDailyCounts resets notification counts daily
-func (m *Monitor) resetDailyCounts() {
-	currentDate := time.Now().Format("2006-01-02")
-	if m.lastResetDate != currentDate {
-		m.notificationCounts = make(map[string]int)
-		m.lastResetDate = currentDate
-		m.logger.Println("Daily notification counts reset")
-	}
+// escalationChanged saves the state file after an escalation was sent or acknowledged
+func (m *Monitor) escalationChanged() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stateChanged = true
+	m.saveStateIfChanged()
 }
 
 // getDiskUsageFloat64 wraps GetDiskUsage to return float64
@@ -100,59 +212,345 @@ func getDiskUsageFloat64() (float64, error) {
 	return float64(usage), err
 }
 
-// checkMetricUsage is a shared function for checking metric usage and sending notifications
-func (m *Monitor) checkMetricUsage(metricKey string, config MonitoringConfig, getUsage func() (float64, error), metricName string) {
-	// Reset daily counts if needed
-	m.resetDailyCounts()
+// newAlertID returns a short random identifier for a new alert
+func newAlertID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%08x", time.Now().UnixNano()&0xffffffff)
+	}
+	return hex.EncodeToString(b)
+}
+
+// updateCheckState records whether a check is firing and returns its current state.
+// A check that starts firing gets a new alert ID; one that recovers stops escalating.
+// A check whose dependency is failing is marked unknown.
+func (m *Monitor) updateCheckState(checkKey string, firing bool) checkState {
+	state, ok := m.checkStates[checkKey]
+	if !ok {
+		state = &checkState{}
+		m.checkStates[checkKey] = state
+	}
+
+	now := time.Now()
+	switch {
+	case firing && !state.Firing:
+		state.Firing = true
+		state.AlertID = newAlertID()
+		state.Since = now
+		state.Transitions = append(state.Transitions, now)
+		m.stateChanged = true
+	case !firing && state.Firing:
+		m.logger.Printf("Check %s recovered (alert %s)", checkKey, state.AlertID)
+		m.escalator.Resolve(state.AlertID)
+		// Close incidents opened by providers such as PagerDuty
+		if state.Last != nil && !state.LastSent.Before(state.Since) {
+			m.notificationManager.Resolve(m.ctx, state.Last)
+		}
+		m.history.RecordIncident(Incident{Check: checkKey, AlertID: state.AlertID, Start: state.Since, End: now})
+		state.Firing = false
+		state.AlertID = ""
+		state.Since = time.Time{}
+		state.Last = nil
+		state.Transitions = append(state.Transitions, now)
+		m.stateChanged = true
+	}
 
-	// Check if we've exceeded daily alert limit
-	if m.notificationCounts[metricKey] >= config.MaxDailyAlerts {
+	m.updateFlapping(checkKey, state, now)
+
+	// A check whose parent is failing cannot be trusted and reports UNKNOWN
+	parent := m.failingDependency(checkKey)
+	if unknown := parent != ""; unknown != state.Unknown {
+		state.Unknown = unknown
+		m.stateChanged = true
+		if unknown {
+			m.logger.Printf("Check %s is UNKNOWN while %s is failing", checkKey, parent)
+		} else {
+			m.logger.Printf("Check %s is no longer UNKNOWN", checkKey)
+		}
+	}
+
+	return *state
+}
+
+// updateFlapping detects a check toggling too often within the flapping window.
+// A single notification is sent when flapping starts; the check is considered
+// stable again once a full window passes without a state change.
+func (m *Monitor) updateFlapping(checkKey string, state *checkState, now time.Time) {
+	if !m.config.Flapping.Enabled {
+		state.Transitions = nil
 		return
 	}
 
+	window := time.Duration(m.config.Flapping.WindowMinutes) * time.Minute
+	recent := state.Transitions[:0]
+	for _, t := range state.Transitions {
+		if now.Sub(t) <= window {
+			recent = append(recent, t)
+		}
+	}
+	state.Transitions = recent
+
+	switch {
+	case !state.Flapping && len(recent) >= m.config.Flapping.Threshold:
+		state.Flapping = true
+		m.stateChanged = true
+		m.logger.Printf("Check %s is flapping (%d state changes in %d minutes)", checkKey, len(recent), m.config.Flapping.WindowMinutes)
+		m.sendFlappingNotification(checkKey, len(recent))
+	case state.Flapping && len(recent) == 0:
+		state.Flapping = false
+		m.stateChanged = true
+		m.logger.Printf("Check %s has stabilised", checkKey)
+	}
+}
+
+// sendFlappingNotification notifies that a check is flapping and its alerts are suppressed
+func (m *Monitor) sendFlappingNotification(checkKey string, changes int) {
+	hostname, serverIP := GetServerInfo()
+	message := &NotificationMessage{
+		Type:  NotificationTypeSlack, // Will be overridden by providers
+		Level: NotificationLevelWarning,
+		Title: fmt.Sprintf("Check Flapping: %s", checkKey),
+		Message: fmt.Sprintf("The %s check changed state %d times in the last %d minutes. Individual alerts are suppressed until it stabilises.",
+			checkKey, changes, m.config.Flapping.WindowMinutes),
+		Hostname:  hostname,
+		IP:        serverIP,
+		Timestamp: time.Now(),
+		Metric:    checkKey,
+		Value:     fmt.Sprintf("%d state changes", changes),
+		Threshold: fmt.Sprintf("%d per %d minutes", m.config.Flapping.Threshold, m.config.Flapping.WindowMinutes),
+		Check:     checkKey,
+		Labels:    m.alertLabels(checkKey),
+	}
+
+	m.notificationManager.Send(m.ctx, message)
+}
+
+// metricSeries maps each built-in check to the name its samples are recorded under
+var metricSeries = map[string]string{
+	"disk":   "disk.used_percent",
+	"cpu":    "cpu.usage",
+	"memory": "memory.used_percent",
+}
+
+// checkMetricUsage is a shared function for checking metric usage and sending notifications
+func (m *Monitor) checkMetricUsage(metricKey string, config MonitoringConfig, getUsage func() (float64, error), metricName string) {
 	usage, err := getUsage()
 	if err != nil {
 		m.logger.Printf("Error checking %s usage: %v", metricName, err)
 		return
 	}
+	m.history.Record(metricSeries[metricKey], usage, time.Now())
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	if usage >= float64(config.Threshold) {
-		level := NotificationLevelWarning
-		if usage >= 95 {
-			level = NotificationLevelError
+	m.checkAnomaly(metricKey, config, usage, metricName)
+	m.checkChange(metricKey, config, metricName)
+
+	// Apply the threshold and providers of any schedule active right now
+	threshold := config.Threshold
+	var providers []string
+	schedule := config.ActiveSchedule(time.Now())
+	if schedule != nil {
+		if schedule.Threshold > 0 {
+			threshold = schedule.Threshold
 		}
+		providers = schedule.Providers
+	}
+
+	firing := usage >= float64(threshold)
+	state := m.updateCheckState(metricKey, firing)
+	defer m.saveStateIfChanged()
+	if !firing || state.Flapping {
+		return
+	}
+
+	if recentlySent(state, m.checkInterval(metricKey)) {
+		return
+	}
+
+	level := NotificationLevelWarning
+	if usage >= 95 {
+		level = NotificationLevelError
+	}
+
+	hostname, serverIP := GetServerInfo()
+	message := &NotificationMessage{
+		Type:      NotificationTypeSlack, // Will be overridden by providers
+		Level:     level,
+		Title:     fmt.Sprintf("%s Usage Alert", metricName),
+		Message:   fmt.Sprintf("%s usage has exceeded the threshold of %d%%", metricName, threshold),
+		Hostname:  hostname,
+		IP:        serverIP,
+		Timestamp: time.Now(),
+		Metric:    fmt.Sprintf("%s Usage", metricName),
+		Value:     fmt.Sprintf("%.2f%%", usage),
+		Threshold: fmt.Sprintf("%d%%", threshold),
+		AlertID:   state.AlertID,
+		Since:     state.Since,
+		Check:     metricKey,
+		Labels:    m.alertLabels(metricKey),
+	}
+
+	if schedule != nil && schedule.Name != "" {
+		message.Message += fmt.Sprintf(" (%s schedule)", schedule.Name)
+	}
+
+	m.notify(message, config.EffectiveRateLimits(), providers)
+}
+
+// recentlySent reports whether the check's alert went out less than half an
+// interval ago, e.g. just before a restart, so it should not be re-sent yet
+func recentlySent(state checkState, interval time.Duration) bool {
+	return !state.LastSent.IsZero() && time.Since(state.LastSent) < interval/2
+}
+
+// notify records an alert raised by a check and sends it unless the check is
+// unknown, inhibited or its rate limits are exhausted. Providers, when given, replace the
+// routing rules for this alert. It reports whether the alert was sent.
+// The caller must hold m.mu.
+func (m *Monitor) notify(message *NotificationMessage, limits []RateLimit, providers []string) bool {
+	state, ok := m.checkStates[message.Check]
+	if ok {
+		state.Last = message
+	}
+
+	// Dependents of a failing check are not notified individually
+	if ok && state.Unknown {
+		m.history.RecordAlert(AlertRecord{
+			Time:         message.Timestamp,
+			Check:        message.Check,
+			Level:        message.Level,
+			AlertID:      message.AlertID,
+			Value:        message.Value,
+			Suppressed:   true,
+			SuppressedBy: m.failingDependency(message.Check),
+		})
+		return false
+	}
 
-		hostname, serverIP := GetServerInfo()
-		message := &NotificationMessage{
-			Type:      NotificationTypeSlack, // Will be overridden by providers
-			Level:     level,
-			Title:     fmt.Sprintf("%s Usage Alert", metricName),
-			Message:   fmt.Sprintf("%s usage has exceeded the threshold of %d%%", metricName, config.Threshold),
-			Hostname:  hostname,
-			IP:        serverIP,
-			Timestamp: time.Now(),
-			Metric:    fmt.Sprintf("%s Usage", metricName),
-			Value:     fmt.Sprintf("%.2f%%", usage),
-			Threshold: fmt.Sprintf("%d%%", config.Threshold),
+	limiter, hasLimiter := m.limiters[message.Check]
+	if !hasLimiter {
+		limiter = &SlidingWindowLimiter{}
+		m.limiters[message.Check] = limiter
+	}
+
+	if limit := limiter.Exceeded(limits, message.Timestamp); limit != nil {
+		if !limiter.Notified {
+			limiter.Notified = true
+			m.stateChanged = true
+			m.sendSuppressedNotice(message, *limit)
 		}
+		return false
+	}
+
+	record := AlertRecord{
+		Time:    message.Timestamp,
+		Check:   message.Check,
+		Level:   message.Level,
+		AlertID: message.AlertID,
+		Value:   message.Value,
+	}
+
+	if source := m.inhibitedBy(message); source != "" {
+		m.logger.Printf("Alert %s for %s suppressed by firing %s alert", message.AlertID, message.Check, source)
+		record.Suppressed = true
+		record.SuppressedBy = source
+		m.history.RecordAlert(record)
+		return false
+	}
 
+	if len(providers) > 0 {
+		m.notificationManager.SendTo(m.ctx, message, providers)
+	} else {
 		m.notificationManager.Send(m.ctx, message)
-		m.notificationCounts[metricKey]++
 	}
+	m.escalator.Track(m.ctx, message)
+	m.history.RecordAlert(record)
+	limiter.Record(message.Timestamp)
+	if ok {
+		state.LastSent = message.Timestamp
+	}
+	m.stateChanged = true
+	return true
+}
+
+// sendSuppressedNotice tells people that further alerts for a check are being dropped
+func (m *Monitor) sendSuppressedNotice(message *NotificationMessage, limit RateLimit) {
+	m.logger.Printf("Rate limit of %s reached for %s, suppressing further alerts", limit, message.Check)
+
+	notice := &NotificationMessage{
+		Type:  message.Type,
+		Level: NotificationLevelWarning,
+		Title: fmt.Sprintf("Further %s alerts suppressed", message.Check),
+		Message: fmt.Sprintf("The alert rate limit for the %s check (%s) has been reached. Further alerts are suppressed until the limit frees up.",
+			message.Check, limit),
+		Hostname:  message.Hostname,
+		IP:        message.IP,
+		Timestamp: message.Timestamp,
+		Metric:    message.Metric,
+		Value:     message.Value,
+		Threshold: message.Threshold,
+		Check:     message.Check,
+		Labels:    message.Labels,
+	}
+
+	m.notificationManager.Send(m.ctx, notice)
+}
+
+// checkInterval returns how often the given built-in check runs
+func (m *Monitor) checkInterval(metricKey string) time.Duration {
+	switch metricKey {
+	case "disk":
+		return m.config.Disk.Interval()
+	case "cpu":
+		return m.config.CPU.Interval()
+	case "memory":
+		return m.config.Memory.Interval()
+	}
+	return 0
+}
+
+// checkJitter returns a random delay of up to the check's configured jitter.
+// It offsets the first check only, so a fleet of hosts started together does
+// not check in lockstep while each host keeps the configured interval.
+func (m *Monitor) checkJitter(metricKey string) time.Duration {
+	var max time.Duration
+	switch metricKey {
+	case "disk":
+		max = m.config.Disk.MaxJitter()
+	case "cpu":
+		max = m.config.CPU.MaxJitter()
+	case "memory":
+		max = m.config.Memory.MaxJitter()
+	}
+	if max <= 0 {
+		return 0
+	}
+	return mathrand.N(max)
+}
+
+// alertLabels returns the host labels merged with the metric label for an alert
+func (m *Monitor) alertLabels(metricKey string) map[string]string {
+	labels := make(map[string]string, len(m.config.Labels)+1)
+	for key, value := range m.config.Labels {
+		labels[key] = value
+	}
+	labels["metric"] = metricKey
+	return labels
 }
 
 // monitorDiskUsage monitors disk usage
 func (m *Monitor) monitorDiskUsage(hostname, serverIP string) {
-	ticker := time.NewTicker(time.Duration(m.config.Disk.CheckInterval) * time.Hour)
-	defer ticker.Stop()
-
-	// Initial check
-	m.checkDiskUsage(hostname, serverIP)
+	// The first check runs after the jitter delay, or immediately without jitter
+	timer := time.NewTimer(m.checkJitter("disk"))
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-timer.C:
 			m.checkDiskUsage(hostname, serverIP)
+			timer.Reset(m.checkInterval("disk"))
 		case <-m.ctx.Done():
 			return
 		}
@@ -166,16 +564,15 @@ func (m *Monitor) checkDiskUsage(hostname, serverIP string) {
 
 // monitorCPUUsage monitors CPU usage
 func (m *Monitor) monitorCPUUsage(hostname, serverIP string) {
-	ticker := time.NewTicker(time.Duration(m.config.CPU.CheckInterval) * time.Minute)
-	defer ticker.Stop()
-
-	// Initial check
-	m.checkCPUUsage(hostname, serverIP)
+	// The first check runs after the jitter delay, or immediately without jitter
+	timer := time.NewTimer(m.checkJitter("cpu"))
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-timer.C:
 			m.checkCPUUsage(hostname, serverIP)
+			timer.Reset(m.checkInterval("cpu"))
 		case <-m.ctx.Done():
 			return
 		}
@@ -189,16 +586,15 @@ func (m *Monitor) checkCPUUsage(hostname, serverIP string) {
 
 // monitorMemoryUsage monitors memory usage
 func (m *Monitor) monitorMemoryUsage(hostname, serverIP string) {
-	ticker := time.NewTicker(time.Duration(m.config.Memory.CheckInterval) * time.Minute)
-	defer ticker.Stop()
-
-	// Initial check
-	m.checkMemoryUsage(hostname, serverIP)
+	// The first check runs after the jitter delay, or immediately without jitter
+	timer := time.NewTimer(m.checkJitter("memory"))
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-timer.C:
 			m.checkMemoryUsage(hostname, serverIP)
+			timer.Reset(m.checkInterval("memory"))
 		case <-m.ctx.Done():
 			return
 		}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingProvider is a ResolvingProvider that records what it receives
+type recordingProvider struct {
+	mu       sync.Mutex
+	sent     []*NotificationMessage
+	resolved []*NotificationMessage
+}
+
+func (rp *recordingProvider) Validate() error           { return nil }
+func (rp *recordingProvider) GetType() NotificationType { return NotificationTypeWebhook }
+
+func (rp *recordingProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.sent = append(rp.sent, message)
+	return nil
+}
+
+func (rp *recordingProvider) Resolve(ctx context.Context, message *NotificationMessage) error {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.resolved = append(rp.resolved, message)
+	return nil
+}
+
+// newTestMonitor returns a monitor without persisted state that delivers to provider
+func newTestMonitor(t *testing.T, provider NotificationProvider) *Monitor {
+	t.Helper()
+
+	logger := log.New(io.Discard, "", 0)
+	manager := NewNotificationManager(logger)
+	if err := manager.AddProvider("test", provider); err != nil {
+		t.Fatalf("AddProvider: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	return &Monitor{
+		config:              &Config{},
+		logger:              logger,
+		ctx:                 ctx,
+		cancel:              cancel,
+		notificationManager: manager,
+		escalator:           NewEscalator(nil, manager, logger),
+		history:             NewMetricHistory(historyRetention),
+		startedAt:           time.Now(),
+		checkStates:         make(map[string]*checkState),
+		limiters:            make(map[string]*SlidingWindowLimiter),
+		baselines:           make(map[string]*Baseline),
+		dependencies:        make(map[string][]string),
+	}
+}
+
+func TestMonitorResolvesAfterFirstAlert(t *testing.T) {
+	provider := &recordingProvider{}
+	m := newTestMonitor(t, provider)
+
+	m.mu.Lock()
+	state := m.updateCheckState("disk", true)
+	sent := m.notify(&NotificationMessage{
+		Level:     NotificationLevelError,
+		Title:     "Disk usage critical",
+		Check:     "disk",
+		AlertID:   state.AlertID,
+		Timestamp: time.Now(),
+	}, nil, nil)
+	if !sent {
+		m.mu.Unlock()
+		t.Fatal("first alert was not sent")
+	}
+	if m.checkStates["disk"].LastSent.IsZero() {
+		m.mu.Unlock()
+		t.Fatal("LastSent not recorded for the first alert of a check")
+	}
+	m.updateCheckState("disk", false)
+	m.mu.Unlock()
+
+	if !m.notificationManager.Wait(5 * time.Second) {
+		t.Fatal("notifications did not finish")
+	}
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if len(provider.sent) != 1 {
+		t.Errorf("sent %d alerts, want 1", len(provider.sent))
+	}
+	if len(provider.resolved) != 1 {
+		t.Fatalf("resolved %d alerts, want 1", len(provider.resolved))
+	}
+	if got := provider.resolved[0].AlertID; got != state.AlertID {
+		t.Errorf("resolved alert %s, want %s", got, state.AlertID)
+	}
+}
+
+func TestRecentlySent(t *testing.T) {
+	tests := []struct {
+		name     string
+		lastSent time.Time
+		want     bool
+	}{
+		{name: "never sent"},
+		{name: "just sent", lastSent: time.Now().Add(-10 * time.Second), want: true},
+		{name: "sent a while ago", lastSent: time.Now().Add(-40 * time.Second)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recentlySent(checkState{LastSent: tt.lastSent}, time.Minute); got != tt.want {
+				t.Errorf("recentlySent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
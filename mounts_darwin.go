@@ -0,0 +1,59 @@
+//go:build darwin
+// +build darwin
+
+package main
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Mount flags from <sys/mount.h>, which the syscall package does not export
+const (
+	mntNoWait     = 2          // return cached statistics instead of querying each filesystem
+	mntLocal      = 0x1000     // filesystem stored locally
+	mntDontBrowse = 0x00100000 // hidden system volume, e.g. /System/Volumes/VM
+)
+
+// getUnixMountUsages lists the local filesystems with getfsstat. MNT_NOWAIT
+// reads cached statistics, so a hung mount cannot block it.
+func getUnixMountUsages() ([]MountUsage, error) {
+	n, err := syscall.Getfsstat(nil, mntNoWait)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count mounts: %w", err)
+	}
+	stats := make([]syscall.Statfs_t, n)
+	if n, err = syscall.Getfsstat(stats, mntNoWait); err != nil {
+		return nil, fmt.Errorf("failed to list mounts: %w", err)
+	}
+
+	var usages []MountUsage
+	for _, stat := range stats[:n] {
+		if stat.Flags&mntLocal == 0 || stat.Flags&mntDontBrowse != 0 || stat.Blocks == 0 {
+			continue
+		}
+		if fsType := cString(stat.Fstypename[:]); fsType == "devfs" || fsType == "autofs" {
+			continue
+		}
+
+		used := stat.Blocks - stat.Bfree
+		usages = append(usages, MountUsage{
+			Mount:       cString(stat.Mntonname[:]),
+			UsedPercent: float64(used) / float64(stat.Blocks) * 100,
+		})
+	}
+
+	return usages, nil
+}
+
+// cString converts a NUL-terminated C character array to a string
+func cString(chars []int8) string {
+	b := make([]byte, 0, len(chars))
+	for _, c := range chars {
+		if c == 0 {
+			break
+		}
+		b = append(b, byte(c))
+	}
+	return string(b)
+}
@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package main
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// getUnixMountUsages - fallback implementation for unsupported platforms
+func getUnixMountUsages() ([]MountUsage, error) {
+	return nil, fmt.Errorf("mount usage is not supported on %s", runtime.GOOS)
+}
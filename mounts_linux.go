@@ -0,0 +1,91 @@
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// statfsTimeout bounds each statfs call, which blocks on a hung mount
+const statfsTimeout = 2 * time.Second
+
+// pseudoFilesystems lists filesystem types that do not represent real storage
+var pseudoFilesystems = map[string]bool{
+	"proc": true, "sysfs": true, "devtmpfs": true, "devpts": true, "tmpfs": true,
+	"cgroup": true, "cgroup2": true, "securityfs": true, "pstore": true, "debugfs": true,
+	"tracefs": true, "configfs": true, "fusectl": true, "mqueue": true, "hugetlbfs": true,
+	"bpf": true, "autofs": true, "binfmt_misc": true, "rpc_pipefs": true, "nsfs": true,
+	"overlay": true, "squashfs": true, "ramfs": true, "efivarfs": true, "selinuxfs": true,
+	"nfsd": true, "fuse.gvfsd-fuse": true, "fuse.portal": true,
+}
+
+// networkFilesystems lists filesystem types served by another host
+var networkFilesystems = map[string]bool{
+	"nfs": true, "nfs4": true, "cifs": true, "smb3": true, "smbfs": true, "9p": true,
+	"afs": true, "ceph": true, "glusterfs": true, "lustre": true, "davfs": true,
+	"fuse.sshfs": true, "fuse.rclone": true, "fuse.s3fs": true,
+}
+
+// getUnixMountUsages reads /proc/mounts and stats every local filesystem
+func getUnixMountUsages() ([]MountUsage, error) {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open /proc/mounts: %w", err)
+	}
+	defer file.Close()
+
+	var usages []MountUsage
+	seenDevices := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || seenDevices[fields[0]] {
+			continue
+		}
+		if fsType := fields[2]; pseudoFilesystems[fsType] || networkFilesystems[fsType] {
+			continue
+		}
+
+		stat, err := statfsWithTimeout(fields[1], statfsTimeout)
+		if err != nil || stat.Blocks == 0 {
+			continue
+		}
+		seenDevices[fields[0]] = true
+
+		used := stat.Blocks - stat.Bfree
+		usages = append(usages, MountUsage{
+			Mount:       fields[1],
+			UsedPercent: float64(used) / float64(stat.Blocks) * 100,
+		})
+	}
+
+	return usages, nil
+}
+
+// statfsWithTimeout stats a filesystem, giving up after timeout. A statfs that
+// hangs keeps its goroutine until the kernel returns.
+func statfsWithTimeout(path string, timeout time.Duration) (*syscall.Statfs_t, error) {
+	type result struct {
+		stat syscall.Statfs_t
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		var r result
+		r.err = syscall.Statfs(path, &r.stat)
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		return &r.stat, r.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("statfs %s timed out after %s", path, timeout)
+	}
+}
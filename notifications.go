@@ -3,21 +3,40 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/x509"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 )
 
 // NotificationType represents the type of notification provider
 type NotificationType string
 
 const (
-	NotificationTypeSlack    NotificationType = "slack"
-	NotificationTypeTelegram NotificationType = "telegram"
-	NotificationTypeDiscord  NotificationType = "discord"
+	NotificationTypeSlack      NotificationType = "slack"
+	NotificationTypeTelegram   NotificationType = "telegram"
+	NotificationTypeDiscord    NotificationType = "discord"
+	NotificationTypeEmail      NotificationType = "email"
+	NotificationTypeWebhook    NotificationType = "webhook"
+	NotificationTypePagerDuty  NotificationType = "pagerduty"
+	NotificationTypeOpsgenie   NotificationType = "opsgenie"
+	NotificationTypeTeams      NotificationType = "teams"
+	NotificationTypeGoogleChat NotificationType = "googlechat"
+	NotificationTypeNtfy       NotificationType = "ntfy"
+	NotificationTypeGotify     NotificationType = "gotify"
+	NotificationTypePushover   NotificationType = "pushover"
+	NotificationTypeMatrix     NotificationType = "matrix"
+	NotificationTypeCommand    NotificationType = "command"
+	NotificationTypeSyslog     NotificationType = "syslog"
+	NotificationTypeJournald   NotificationType = "journald"
 )
 
 // NotificationLevel represents the severity level of a notification
@@ -29,6 +48,15 @@ const (
 	NotificationLevelError   NotificationLevel = "error"
 )
 
+// isValidNotificationLevel reports whether level names a known notification level
+func isValidNotificationLevel(level string) bool {
+	switch NotificationLevel(level) {
+	case NotificationLevelInfo, NotificationLevelWarning, NotificationLevelError:
+		return true
+	}
+	return false
+}
+
 // NotificationMessage represents a notification message
 type NotificationMessage struct {
 	Type      NotificationType  `json:"type"`
@@ -41,6 +69,15 @@ type NotificationMessage struct {
 	Metric    string            `json:"metric"`
 	Value     string            `json:"value"`
 	Threshold string            `json:"threshold"`
+	AlertID   string            `json:"alert_id,omitempty"`
+	Check     string            `json:"check,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+
+	// Since is when the check started firing; zero for messages not tied to a firing check
+	Since time.Time `json:"since,omitzero"`
+
+	// Group holds the individual alerts when several are sent as one notification
+	Group []*NotificationMessage `json:"alerts,omitempty"`
 }
 
 // NotificationProvider interface defines methods for notification providers
@@ -50,11 +87,34 @@ type NotificationProvider interface {
 	GetType() NotificationType
 }
 
+// ResolvingProvider is implemented by providers that track incidents and can
+// close them when the alert that opened them clears
+type ResolvingProvider interface {
+	NotificationProvider
+	Resolve(ctx context.Context, message *NotificationMessage) error
+}
+
+// namedProvider pairs a notification provider with the name routes refer to it by
+type namedProvider struct {
+	name       string
+	provider   NotificationProvider
+	rateLimits []RateLimit
+}
+
 // NotificationManager manages multiple notification providers
 type NotificationManager struct {
-	providers []NotificationProvider
+	providers []namedProvider
+	router    *Router
 	logger    *log.Logger
 	client    *http.Client
+	inFlight  sync.WaitGroup
+
+	// mu guards alert grouping, in-flight deduplication and rate limiting
+	mu          sync.Mutex
+	groupWindow time.Duration
+	groups      map[string]*alertGroup
+	sendingNow  map[string]bool
+	limiters    map[string]*SlidingWindowLimiter
 }
 
 // NewNotificationManager creates a new notification manager
@@ -69,92 +129,313 @@ func NewNotificationManager(logger *log.Logger) *NotificationManager {
 	}
 
 	return &NotificationManager{
-		providers: make([]NotificationProvider, 0),
-		logger:    logger,
-		client:    client,
+		providers:  make([]namedProvider, 0),
+		logger:     logger,
+		client:     client,
+		groups:     make(map[string]*alertGroup),
+		sendingNow: make(map[string]bool),
+		limiters:   make(map[string]*SlidingWindowLimiter),
 	}
 }
 
-// AddProvider adds a notification provider to the manager
-func (nm *NotificationManager) AddProvider(provider NotificationProvider) error {
+// AddProvider adds a notification provider to the manager under the given name.
+// Alerts sent to the provider are limited by the given sliding-window rate limits.
+func (nm *NotificationManager) AddProvider(name string, provider NotificationProvider, rateLimits ...RateLimit) error {
 	if err := provider.Validate(); err != nil {
 		return fmt.Errorf("invalid provider %s: %w", provider.GetType(), err)
 	}
-	nm.providers = append(nm.providers, provider)
+	nm.providers = append(nm.providers, namedProvider{name: name, provider: provider, rateLimits: rateLimits})
 	return nil
 }
 
-// Send sends a notification message to all enabled providers concurrently
+// SetRouter sets the router used to select providers for each message
+func (nm *NotificationManager) SetRouter(router *Router) {
+	nm.router = router
+}
+
+// Send sends a notification message to the providers selected by the router concurrently
 func (nm *NotificationManager) Send(ctx context.Context, message *NotificationMessage) {
 	if len(nm.providers) == 0 {
 		nm.logger.Println("No notification providers configured")
 		return
 	}
 
-	// Send to all providers concurrently
+	var names []string
+	if nm.router != nil {
+		names = nm.router.Route(message)
+	}
+
+	// Fall back to every provider when no route applies
+	if len(names) == 0 {
+		nm.dispatch(ctx, message, nm.providers)
+		return
+	}
+
+	nm.SendTo(ctx, message, names)
+}
+
+// SendTo sends a notification message to the named providers concurrently
+func (nm *NotificationManager) SendTo(ctx context.Context, message *NotificationMessage, names []string) {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var selected []namedProvider
+	for _, p := range nm.providers {
+		if wanted[p.name] {
+			selected = append(selected, p)
+		}
+	}
+
+	if len(selected) == 0 {
+		nm.logger.Printf("No enabled notification providers match %s", strings.Join(names, ", "))
+		return
+	}
+
+	nm.dispatch(ctx, message, selected)
+}
+
+// SendFollowUp sends a message produced by a provider, such as a command's
+// result, to the providers selected by the router. Command providers are
+// skipped so that results never run further commands.
+func (nm *NotificationManager) SendFollowUp(ctx context.Context, message *NotificationMessage) {
+	var names []string
+	if nm.router != nil {
+		names = nm.router.Route(message)
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var selected []namedProvider
+	for _, p := range nm.providers {
+		if p.provider.GetType() == NotificationTypeCommand {
+			continue
+		}
+		// Fall back to every provider when no route applies
+		if len(names) == 0 || wanted[p.name] {
+			selected = append(selected, p)
+		}
+	}
+
+	if len(selected) > 0 {
+		nm.dispatch(ctx, message, selected)
+	}
+}
+
+// dispatch sends a notification message to the given providers concurrently,
+// batching alerts when a grouping window is set
+func (nm *NotificationManager) dispatch(ctx context.Context, message *NotificationMessage, providers []namedProvider) {
+	nm.mu.Lock()
+	grouping := nm.groupWindow > 0
+	nm.mu.Unlock()
+
+	for _, provider := range providers {
+		if grouping && isGroupable(message) {
+			nm.enqueue(ctx, provider, message)
+			continue
+		}
+		nm.deliver(ctx, provider, message)
+	}
+}
+
+// deliver sends a message to a single provider in the background, skipping it
+// if an identical message to the same provider is still in flight
+func (nm *NotificationManager) deliver(ctx context.Context, provider namedProvider, message *NotificationMessage) {
+	key := provider.name + "\x00" + messageFingerprint(message)
+
+	nm.mu.Lock()
+	if isAlert(message) && len(provider.rateLimits) > 0 && !nm.allowProvider(provider, message) {
+		nm.mu.Unlock()
+		return
+	}
+	if nm.sendingNow[key] {
+		nm.mu.Unlock()
+		nm.logger.Printf("Dropping duplicate notification %q for %s, identical message still in flight", message.Title, provider.name)
+		return
+	}
+	nm.sendingNow[key] = true
+	nm.mu.Unlock()
+
+	nm.inFlight.Add(1)
+	go func(p namedProvider) {
+		defer nm.inFlight.Done()
+		defer func() {
+			nm.mu.Lock()
+			delete(nm.sendingNow, key)
+			nm.mu.Unlock()
+		}()
+
+		if err := p.provider.Send(ctx, message); err != nil {
+			nm.logger.Printf("Failed to send notification via %s: %v", p.name, err)
+		} else {
+			nm.logger.Printf("Notification sent successfully via %s", p.name)
+		}
+	}(provider)
+}
+
+// incidentKey returns the key that ties every alert of a host's check to one
+// incident, so repeated alerts and escalations update it instead of opening new ones
+func incidentKey(message *NotificationMessage) string {
+	check := message.Check
+	if check == "" {
+		check = message.AlertID
+	}
+	return fmt.Sprintf("%s/%s/%s", appName, message.Hostname, check)
+}
+
+// truncate shortens s to at most max bytes without splitting a UTF-8 sequence,
+// marking the cut with an ellipsis
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	cut := max - len("...")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "..."
+}
+
+// Resolve tells every provider that tracks incidents that the alert in message
+// has cleared. Providers ignore resolutions for incidents they never opened, so
+// routing is not applied.
+func (nm *NotificationManager) Resolve(ctx context.Context, message *NotificationMessage) {
 	for _, provider := range nm.providers {
-		go func(p NotificationProvider) {
-			if err := p.Send(ctx, message); err != nil {
-				nm.logger.Printf("Failed to send notification via %s: %v", p.GetType(), err)
+		resolver, ok := provider.provider.(ResolvingProvider)
+		if !ok {
+			continue
+		}
+
+		nm.inFlight.Add(1)
+		go func(name string) {
+			defer nm.inFlight.Done()
+
+			if err := resolver.Resolve(ctx, message); err != nil {
+				nm.logger.Printf("Failed to resolve alert %s via %s: %v", message.AlertID, name, err)
 			} else {
-				nm.logger.Printf("Notification sent successfully via %s", p.GetType())
+				nm.logger.Printf("Alert %s resolved via %s", message.AlertID, name)
 			}
-		}(provider)
+		}(provider.name)
 	}
 }
 
-// sendHTTPRequest is a shared function for sending HTTP requests with retry logic
+// Wait blocks until all in-flight notifications are delivered or the timeout expires.
+// It reports whether every notification finished in time.
+func (nm *NotificationManager) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		nm.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// httpRequest describes a notification request sent by sendHTTP
+type httpRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// sendHTTPRequest is a shared function for POSTing JSON payloads with retry logic
 func sendHTTPRequest(ctx context.Context, client *http.Client, url string, payload interface{}) error {
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
+	return sendHTTP(ctx, client, httpRequest{
+		Method:  http.MethodPost,
+		URL:     url,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    jsonData,
+	})
+}
+
+// sendHTTP sends a request with retry logic. Any 2xx response counts as success.
+func sendHTTP(ctx context.Context, client *http.Client, request httpRequest) error {
 	const maxRetries = 3
 	const retryDelay = 5 * time.Second
 
+	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+		if attempt > 1 {
+			select {
+			case <-time.After(retryDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, request.Method, request.URL, bytes.NewReader(request.Body))
 		if err != nil {
 			return fmt.Errorf("failed to create request: %w", err)
 		}
 
-		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("User-Agent", "ServerHealth/1.0")
+		for name, value := range request.Headers {
+			req.Header.Set(name, value)
+		}
 
 		resp, err := client.Do(req)
 		if err != nil {
-			if attempt < maxRetries {
-				time.Sleep(retryDelay)
-				continue
-			}
-			return fmt.Errorf("failed to send request: %w", err)
+			lastErr = fmt.Errorf("failed to send request: %w", err)
+			continue
 		}
 
-		defer resp.Body.Close()
+		// Drain and close the body so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
 
-		if resp.StatusCode == http.StatusOK {
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 			return nil
 		}
-
-		if attempt < maxRetries {
-			time.Sleep(retryDelay)
-		}
+		lastErr = fmt.Errorf("unexpected status %s", resp.Status)
 	}
 
-	return fmt.Errorf("failed to send notification after %d attempts", maxRetries)
+	return fmt.Errorf("failed to send notification after %d attempts: %w", maxRetries, lastErr)
+}
+
+// loadCAFile reads a PEM file of the CA certificates to trust instead of the
+// system roots, for servers with a private CA
+func loadCAFile(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ca_file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("ca_file %s contains no certificates", path)
+	}
+	return pool, nil
 }
 
 // SlackProvider implements NotificationProvider for Slack
 type SlackProvider struct {
 	WebhookURL string
+	Template   TemplateConfig
+	template   *MessageTemplate
 	client     *http.Client
 }
 
-// NewSlackProvider creates a new Slack notification provider
-func NewSlackProvider(webhookURL string, client *http.Client) *SlackProvider {
+// NewSlackProvider creates a new Slack notification provider. Without title or
+// body templates the default layout is used.
+func NewSlackProvider(webhookURL string, tmpl TemplateConfig, client *http.Client) *SlackProvider {
 	return &SlackProvider{
 		WebhookURL: webhookURL,
+		Template:   tmpl,
 		client:     client,
 	}
 }
@@ -173,6 +454,12 @@ func (sp *SlackProvider) Validate() error {
 		return fmt.Errorf("webhook URL must be from hooks.slack.com")
 	}
 
+	tmpl, err := sp.Template.Load()
+	if err != nil {
+		return err
+	}
+	sp.template = tmpl
+
 	return nil
 }
 
@@ -183,24 +470,11 @@ func (sp *SlackProvider) GetType() NotificationType {
 
 // Send sends a notification to Slack
 func (sp *SlackProvider) Send(ctx context.Context, message *NotificationMessage) error {
-	// Determine emoji based on level
-	var emoji string
-	switch message.Level {
-	case NotificationLevelInfo:
-		emoji = "ℹ️"
-	case NotificationLevelWarning:
-		emoji = "⚠️"
-	case NotificationLevelError:
-		emoji = "❌"
-	default:
-		emoji = "ℹ️"
-	}
+	title, body := sp.template.Render(message, slackMarkdown.title, slackMarkdown.body)
 
 	// Create Slack payload
 	payload := map[string]interface{}{
-		"text": fmt.Sprintf("%s *%s*\n%s\n\n*Server:* %s (%s)\n*Metric:* %s\n*Value:* %s\n*Threshold:* %s\n*Time:* %s",
-			emoji, message.Title, message.Message, message.Hostname, message.IP,
-			message.Metric, message.Value, message.Threshold, message.Timestamp.Format("2006-01-02 15:04:05")),
+		"text": title + "\n" + body,
 	}
 
 	return sendHTTPRequest(ctx, sp.client, sp.WebhookURL, payload)
@@ -210,14 +484,18 @@ func (sp *SlackProvider) Send(ctx context.Context, message *NotificationMessage)
 type TelegramProvider struct {
 	BotToken string
 	ChatID   string
+	Template TemplateConfig
+	template *MessageTemplate
 	client   *http.Client
 }
 
-// NewTelegramProvider creates a new Telegram notification provider
-func NewTelegramProvider(botToken, chatID string, client *http.Client) *TelegramProvider {
+// NewTelegramProvider creates a new Telegram notification provider. Without
+// title or body templates the default layout is used.
+func NewTelegramProvider(botToken, chatID string, tmpl TemplateConfig, client *http.Client) *TelegramProvider {
 	return &TelegramProvider{
 		BotToken: botToken,
 		ChatID:   chatID,
+		Template: tmpl,
 		client:   client,
 	}
 }
@@ -232,6 +510,12 @@ func (tp *TelegramProvider) Validate() error {
 		return fmt.Errorf("chat ID is required")
 	}
 
+	tmpl, err := tp.Template.Load()
+	if err != nil {
+		return err
+	}
+	tp.template = tmpl
+
 	return nil
 }
 
@@ -242,25 +526,12 @@ func (tp *TelegramProvider) GetType() NotificationType {
 
 // Send sends a notification to Telegram
 func (tp *TelegramProvider) Send(ctx context.Context, message *NotificationMessage) error {
-	// Determine emoji based on level
-	var emoji string
-	switch message.Level {
-	case NotificationLevelInfo:
-		emoji = "ℹ️"
-	case NotificationLevelWarning:
-		emoji = "⚠️"
-	case NotificationLevelError:
-		emoji = "❌"
-	default:
-		emoji = "ℹ️"
-	}
+	title, body := tp.template.Render(message, telegramMarkdown.title, telegramMarkdown.body)
 
 	// Create Telegram payload
 	payload := map[string]interface{}{
-		"chat_id": tp.ChatID,
-		"text": fmt.Sprintf("%s *%s*\n%s\n\n*Server:* %s (%s)\n*Metric:* %s\n*Value:* %s\n*Threshold:* %s\n*Time:* %s",
-			emoji, message.Title, message.Message, message.Hostname, message.IP,
-			message.Metric, message.Value, message.Threshold, message.Timestamp.Format("2006-01-02 15:04:05")),
+		"chat_id":    tp.ChatID,
+		"text":       title + "\n" + body,
 		"parse_mode": "Markdown",
 	}
 
@@ -269,16 +540,155 @@ func (tp *TelegramProvider) Send(ctx context.Context, message *NotificationMessa
 	return sendHTTPRequest(ctx, tp.client, apiURL, payload)
 }
 
+// markdownDialect is the Markdown flavour of a chat service
+type markdownDialect struct {
+	// escape makes plain text safe outside formatting; nil leaves it unchanged
+	escape func(string) string
+	// bold renders text in bold
+	bold func(string) string
+}
+
+// slackMarkdown is Slack's mrkdwn, which leaves stray markers as plain text
+var slackMarkdown = markdownDialect{
+	bold: func(s string) string { return "*" + s + "*" },
+}
+
+// telegramMarkdown is Telegram's legacy Markdown, which rejects messages with
+// unbalanced markers. Markers are escaped with a backslash outside entities;
+// inside bold text a "*" has to close the entity, be escaped and reopen it.
+var telegramMarkdown = markdownDialect{
+	escape: strings.NewReplacer("_", `\_`, "*", `\*`, "`", "\\`", "[", `\[`).Replace,
+	bold:   func(s string) string { return "*" + strings.ReplaceAll(s, "*", `*\**`) + "*" },
+}
+
+// title renders the default heading of a notification
+func (d markdownDialect) title(message *NotificationMessage) string {
+	return fmt.Sprintf("%s %s", levelEmoji(message.Level), d.bold(message.Title))
+}
+
+// body renders the default body of a notification
+func (d markdownDialect) body(message *NotificationMessage) string {
+	message = d.escapeFields(message)
+
+	// Grouped alerts are listed one per line, after a blank line
+	if len(message.Group) > 0 {
+		return fmt.Sprintf("\n%s\n\n*Server:* %s (%s)\n*Time:* %s",
+			groupLines(message, d.bold),
+			message.Hostname, message.IP, message.Timestamp.Format("2006-01-02 15:04:05"))
+	}
+
+	body := fmt.Sprintf("%s\n\n*Server:* %s (%s)\n*Metric:* %s\n*Value:* %s\n*Threshold:* %s\n*Time:* %s",
+		message.Message, message.Hostname, message.IP,
+		message.Metric, message.Value, message.Threshold, message.Timestamp.Format("2006-01-02 15:04:05"))
+	if message.AlertID != "" {
+		body += fmt.Sprintf("\n*Alert ID:* `%s`", message.AlertID)
+	}
+	return body
+}
+
+// escapeFields returns a copy of the message whose free text is escaped.
+// Titles are left alone because they are always rendered in bold.
+func (d markdownDialect) escapeFields(message *NotificationMessage) *NotificationMessage {
+	if d.escape == nil {
+		return message
+	}
+
+	escaped := *message
+	escaped.Message = d.escape(message.Message)
+	escaped.Hostname = d.escape(message.Hostname)
+	escaped.IP = d.escape(message.IP)
+	escaped.Metric = d.escape(message.Metric)
+	escaped.Value = d.escape(message.Value)
+	escaped.Threshold = d.escape(message.Threshold)
+	escaped.Group = make([]*NotificationMessage, len(message.Group))
+	for i, alert := range message.Group {
+		escaped.Group[i] = d.escapeFields(alert)
+	}
+	return &escaped
+}
+
+// levelColor returns the RGB colour used for a notification level
+func levelColor(level NotificationLevel) int {
+	switch level {
+	case NotificationLevelWarning:
+		return 0xf39c12 // Orange
+	case NotificationLevelError:
+		return 0xe74c3c // Red
+	default:
+		return 0x3498db // Blue
+	}
+}
+
+// alertFacts returns the server, metric, value, threshold and alert ID of an
+// alert as name/value pairs, skipping the server when withServer is false
+func alertFacts(message *NotificationMessage, withServer bool) [][2]string {
+	var facts [][2]string
+	if withServer {
+		facts = append(facts, [2]string{"Server", fmt.Sprintf("%s (%s)", message.Hostname, message.IP)})
+	}
+	facts = append(facts,
+		[2]string{"Metric", message.Metric},
+		[2]string{"Value", message.Value},
+		[2]string{"Threshold", message.Threshold},
+	)
+	if message.AlertID != "" {
+		facts = append(facts, [2]string{"Alert ID", message.AlertID})
+	}
+	return facts
+}
+
+// pushText renders the plain-text body of a push notification; the title is sent separately
+func pushText(message *NotificationMessage) string {
+	if len(message.Group) > 0 {
+		return groupLines(message, func(s string) string { return s })
+	}
+
+	lines := []string{message.Message, ""}
+	for _, fact := range alertFacts(message, false) {
+		lines = append(lines, fact[0]+": "+fact[1])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// upperSnake turns a label name into an upper case identifier for environment
+// variables and journal fields, replacing other characters with underscores
+func upperSnake(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
 // DiscordProvider implements NotificationProvider for Discord
 type DiscordProvider struct {
 	WebhookURL string
+	Template   TemplateConfig
+	template   *MessageTemplate
 	client     *http.Client
 }
 
-// NewDiscordProvider creates a new Discord notification provider
-func NewDiscordProvider(webhookURL string, client *http.Client) *DiscordProvider {
+// NewDiscordProvider creates a new Discord notification provider. Templates
+// replace the embed title and description.
+func NewDiscordProvider(webhookURL string, tmpl TemplateConfig, client *http.Client) *DiscordProvider {
 	return &DiscordProvider{
 		WebhookURL: webhookURL,
+		Template:   tmpl,
 		client:     client,
 	}
 }
@@ -297,6 +707,12 @@ func (dp *DiscordProvider) Validate() error {
 		return fmt.Errorf("webhook URL must be from discord.com or discordapp.com")
 	}
 
+	tmpl, err := dp.Template.Load()
+	if err != nil {
+		return err
+	}
+	dp.template = tmpl
+
 	return nil
 }
 
@@ -307,47 +723,67 @@ func (dp *DiscordProvider) GetType() NotificationType {
 
 // Send sends a notification to Discord
 func (dp *DiscordProvider) Send(ctx context.Context, message *NotificationMessage) error {
-	// Determine color based on level
-	var color int
-	switch message.Level {
-	case NotificationLevelInfo:
-		color = 0x3498db // Blue
-	case NotificationLevelWarning:
-		color = 0xf39c12 // Orange
-	case NotificationLevelError:
-		color = 0xe74c3c // Red
-	default:
-		color = 0x3498db // Blue
+	title, description := dp.template.Render(message, alertTitle, alertText)
+
+	fields := []map[string]interface{}{
+		{
+			"name":   "Server",
+			"value":  fmt.Sprintf("%s (%s)", message.Hostname, message.IP),
+			"inline": true,
+		},
+		{
+			"name":   "Metric",
+			"value":  message.Metric,
+			"inline": true,
+		},
+		{
+			"name":   "Value",
+			"value":  message.Value,
+			"inline": true,
+		},
+		{
+			"name":   "Threshold",
+			"value":  message.Threshold,
+			"inline": true,
+		},
+	}
+	if message.AlertID != "" {
+		fields = append(fields, map[string]interface{}{
+			"name":   "Alert ID",
+			"value":  message.AlertID,
+			"inline": true,
+		})
 	}
 
-	// Create Discord embed
-	embed := map[string]interface{}{
-		"title":       message.Title,
-		"description": message.Message,
-		"color":       color,
-		"fields": []map[string]interface{}{
+	// Grouped alerts get one field each
+	if len(message.Group) > 0 {
+		fields = []map[string]interface{}{
 			{
 				"name":   "Server",
 				"value":  fmt.Sprintf("%s (%s)", message.Hostname, message.IP),
-				"inline": true,
-			},
-			{
-				"name":   "Metric",
-				"value":  message.Metric,
-				"inline": true,
-			},
-			{
-				"name":   "Value",
-				"value":  message.Value,
-				"inline": true,
+				"inline": false,
 			},
-			{
-				"name":   "Threshold",
-				"value":  message.Threshold,
-				"inline": true,
-			},
-		},
-		"timestamp": message.Timestamp.Format(time.RFC3339),
+		}
+		for _, alert := range message.Group {
+			value := fmt.Sprintf("%s: %s (threshold %s)", alert.Metric, alert.Value, alert.Threshold)
+			if alert.AlertID != "" {
+				value += fmt.Sprintf("\nAlert ID: %s", alert.AlertID)
+			}
+			fields = append(fields, map[string]interface{}{
+				"name":   fmt.Sprintf("%s %s", levelEmoji(alert.Level), alert.Title),
+				"value":  value,
+				"inline": false,
+			})
+		}
+	}
+
+	// Create Discord embed
+	embed := map[string]interface{}{
+		"title":       title,
+		"description": description,
+		"color":       levelColor(message.Level),
+		"fields":      fields,
+		"timestamp":   message.Timestamp.Format(time.RFC3339),
 	}
 
 	payload := map[string]interface{}{
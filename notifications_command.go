@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCommandTimeout       = 30 * time.Second
+	defaultCommandMaxConcurrent = 1
+
+	// commandOutputLimit caps the output kept from a command for logs and results
+	commandOutputLimit = 4096
+)
+
+// CommandProvider implements NotificationProvider by running a local command for
+// each alert, e.g. to clear a cache or restart a unit. The alert is passed as
+// JSON on stdin and as SERVERHEALTH_* environment variables.
+type CommandProvider struct {
+	Name          string
+	Command       string
+	Args          []string
+	Timeout       time.Duration
+	MaxConcurrent int
+	logger        *log.Logger
+	slots         chan struct{}
+
+	// report, when set, receives a follow-up message with each command's result
+	report func(ctx context.Context, message *NotificationMessage)
+}
+
+// NewCommandProvider creates a new command notification provider. At most
+// maxConcurrent commands run at once; each is killed after timeout.
+func NewCommandProvider(name, command string, args []string, timeout time.Duration, maxConcurrent int, logger *log.Logger) *CommandProvider {
+	if timeout == 0 {
+		timeout = defaultCommandTimeout
+	}
+	if maxConcurrent == 0 {
+		maxConcurrent = defaultCommandMaxConcurrent
+	}
+
+	return &CommandProvider{
+		Name:          name,
+		Command:       command,
+		Args:          args,
+		Timeout:       timeout,
+		MaxConcurrent: maxConcurrent,
+		logger:        logger,
+		slots:         make(chan struct{}, max(maxConcurrent, 1)),
+	}
+}
+
+// SetReporter sets the function that receives the result of each command
+func (cp *CommandProvider) SetReporter(report func(ctx context.Context, message *NotificationMessage)) {
+	cp.report = report
+}
+
+// Validate validates the command provider configuration
+func (cp *CommandProvider) Validate() error {
+	if cp.Command == "" {
+		return fmt.Errorf("command is required")
+	}
+
+	if _, err := exec.LookPath(cp.Command); err != nil {
+		return fmt.Errorf("command %q not found or not executable", cp.Command)
+	}
+
+	if cp.Timeout < 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	if cp.MaxConcurrent < 1 {
+		return fmt.Errorf("max_concurrent must be at least 1")
+	}
+
+	return nil
+}
+
+// GetType returns the notification type
+func (cp *CommandProvider) GetType() NotificationType {
+	return NotificationTypeCommand
+}
+
+// Send runs the command once for each alert in the message. Informational
+// messages such as digests and command results do not run the command.
+func (cp *CommandProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	alerts := []*NotificationMessage{message}
+	if len(message.Group) > 0 {
+		alerts = message.Group
+	}
+
+	var failed []string
+	for _, alert := range alerts {
+		if !isAlert(alert) {
+			continue
+		}
+		if err := cp.run(ctx, alert); err != nil {
+			failed = append(failed, err.Error())
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// run runs the command for a single alert once a concurrency slot is free,
+// logs its output and reports the result
+func (cp *CommandProvider) run(ctx context.Context, message *NotificationMessage) error {
+	select {
+	case cp.slots <- struct{}{}:
+		defer func() { <-cp.slots }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	input, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, cp.Timeout)
+	defer cancel()
+
+	output := &limitedBuffer{limit: commandOutputLimit}
+	cmd := exec.CommandContext(runCtx, cp.Command, cp.Args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.Env = append(os.Environ(), commandEnv(message)...)
+	// On timeout kill the whole process group, so children such as a sleep
+	// in a shell script don't outlive the command
+	setPlatformSysProcAttr(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	cmd.WaitDelay = 5 * time.Second
+
+	start := time.Now()
+	err = cmd.Run()
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if runCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %s", cp.Timeout)
+	}
+
+	if text := strings.TrimSpace(output.String()); text != "" {
+		cp.logger.Printf("Command %s output for alert %s:\n%s", cp.Name, message.AlertID, text)
+	}
+	if err != nil {
+		cp.logger.Printf("Command %s for alert %s failed after %s: %v", cp.Name, message.AlertID, elapsed, err)
+	} else {
+		cp.logger.Printf("Command %s for alert %s finished in %s", cp.Name, message.AlertID, elapsed)
+	}
+
+	if cp.report != nil {
+		cp.report(ctx, cp.resultMessage(message, output.String(), elapsed, err))
+	}
+
+	if err != nil {
+		return fmt.Errorf("command %s failed: %w", cp.Name, err)
+	}
+	return nil
+}
+
+// resultMessage builds the follow-up message describing a command's result.
+// It has no alert ID, so it is not escalated and never runs commands itself.
+func (cp *CommandProvider) resultMessage(alert *NotificationMessage, output string, elapsed time.Duration, err error) *NotificationMessage {
+	level := NotificationLevelInfo
+	title := fmt.Sprintf("Command %s succeeded", cp.Name)
+	status := fmt.Sprintf("finished in %s", elapsed)
+	if err != nil {
+		level = NotificationLevelWarning
+		title = fmt.Sprintf("Command %s failed", cp.Name)
+		status = fmt.Sprintf("failed after %s: %v", elapsed, err)
+	}
+
+	text := fmt.Sprintf("%s for \"%s\" %s.", cp.Name, alert.Title, status)
+	if output = strings.TrimSpace(output); output != "" {
+		text += "\n\nOutput:\n" + truncate(output, 1000)
+	}
+
+	return &NotificationMessage{
+		Type:      alert.Type,
+		Level:     level,
+		Title:     title,
+		Message:   text,
+		Hostname:  alert.Hostname,
+		IP:        alert.IP,
+		Timestamp: time.Now(),
+		Metric:    alert.Metric,
+		Value:     alert.Value,
+		Threshold: alert.Threshold,
+		Check:     alert.Check,
+		Labels:    alert.Labels,
+	}
+}
+
+// commandEnv returns the SERVERHEALTH_* environment variables describing an alert.
+// Labels are passed as SERVERHEALTH_LABEL_<NAME>.
+func commandEnv(message *NotificationMessage) []string {
+	env := []string{
+		"SERVERHEALTH_LEVEL=" + string(message.Level),
+		"SERVERHEALTH_TITLE=" + message.Title,
+		"SERVERHEALTH_MESSAGE=" + message.Message,
+		"SERVERHEALTH_HOSTNAME=" + message.Hostname,
+		"SERVERHEALTH_IP=" + message.IP,
+		"SERVERHEALTH_TIMESTAMP=" + message.Timestamp.Format(time.RFC3339),
+		"SERVERHEALTH_METRIC=" + message.Metric,
+		"SERVERHEALTH_VALUE=" + message.Value,
+		"SERVERHEALTH_THRESHOLD=" + message.Threshold,
+		"SERVERHEALTH_ALERT_ID=" + message.AlertID,
+		"SERVERHEALTH_CHECK=" + message.Check,
+	}
+
+	for key, value := range message.Labels {
+		env = append(env, "SERVERHEALTH_LABEL_"+upperSnake(key)+"="+value)
+	}
+
+	return env
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+// Write implements io.Writer, always reporting the full length as written so
+// the command is not interrupted by a short write
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+	} else {
+		b.buf.Write(p)
+	}
+	return len(p), nil
+}
+
+// String returns the captured output, marking truncated output
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.buf.String() + "\n[output truncated]"
+	}
+	return b.buf.String()
+}
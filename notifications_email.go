@@ -0,0 +1,458 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Email TLS modes
+const (
+	EmailTLSStartTLS = "starttls"
+	EmailTLSImplicit = "tls"
+	EmailTLSNone     = "none"
+)
+
+// Email authentication mechanisms
+const (
+	EmailAuthPlain = "plain"
+	EmailAuthLogin = "login"
+)
+
+// EmailProvider implements NotificationProvider for email over SMTP
+type EmailProvider struct {
+	Host     string
+	Port     int
+	From     string
+	To       []string
+	Username string
+	Password string
+	TLS      string
+	Auth     string
+	CAFile   string
+	Template TemplateConfig
+	template *MessageTemplate
+	timeout  time.Duration
+}
+
+// NewEmailProvider creates a new email notification provider.
+// The port defaults to 587 for STARTTLS, 465 for implicit TLS and 25 without TLS.
+// Templates replace the subject and the message body. A CA file, when given,
+// replaces the system roots for verifying the server certificate.
+func NewEmailProvider(host string, port int, from string, to []string, username, password, tlsMode, auth, caFile string,
+	tmpl TemplateConfig) *EmailProvider {
+	if tlsMode == "" {
+		tlsMode = EmailTLSStartTLS
+	}
+	if auth == "" {
+		auth = EmailAuthPlain
+	}
+	if port == 0 {
+		port = defaultEmailPort(tlsMode)
+	}
+
+	return &EmailProvider{
+		Host:     host,
+		Port:     port,
+		From:     from,
+		To:       to,
+		Username: username,
+		Password: password,
+		TLS:      tlsMode,
+		Auth:     auth,
+		CAFile:   caFile,
+		Template: tmpl,
+		timeout:  30 * time.Second,
+	}
+}
+
+// defaultEmailPort returns the standard SMTP port for a TLS mode
+func defaultEmailPort(tlsMode string) int {
+	switch tlsMode {
+	case EmailTLSImplicit:
+		return 465
+	case EmailTLSNone:
+		return 25
+	default:
+		return 587
+	}
+}
+
+// Validate validates the email provider configuration
+func (ep *EmailProvider) Validate() error {
+	if ep.Host == "" {
+		return fmt.Errorf("SMTP host is required")
+	}
+
+	if ep.Port < 1 || ep.Port > 65535 {
+		return fmt.Errorf("SMTP port must be between 1 and 65535")
+	}
+
+	if _, err := mail.ParseAddress(ep.From); err != nil {
+		return fmt.Errorf("invalid from address %q", ep.From)
+	}
+
+	if len(ep.To) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+	for _, to := range ep.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("invalid recipient address %q", to)
+		}
+	}
+
+	switch ep.TLS {
+	case EmailTLSStartTLS, EmailTLSImplicit, EmailTLSNone:
+	default:
+		return fmt.Errorf("TLS mode must be one of: starttls, tls, none")
+	}
+
+	switch ep.Auth {
+	case EmailAuthPlain, EmailAuthLogin:
+	default:
+		return fmt.Errorf("auth must be one of: plain, login")
+	}
+
+	if ep.Username != "" && ep.Password == "" {
+		return fmt.Errorf("password is required when a username is set")
+	}
+
+	if ep.CAFile != "" {
+		if ep.TLS == EmailTLSNone {
+			return fmt.Errorf("ca_file is only used with starttls or tls")
+		}
+		if _, err := ep.tlsConfig(); err != nil {
+			return err
+		}
+	}
+
+	tmpl, err := ep.Template.Load()
+	if err != nil {
+		return err
+	}
+	ep.template = tmpl
+
+	return nil
+}
+
+// GetType returns the notification type
+func (ep *EmailProvider) GetType() NotificationType {
+	return NotificationTypeEmail
+}
+
+// Send sends a notification email to every recipient
+func (ep *EmailProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	body, err := ep.buildMessage(message)
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
+
+	client, err := ep.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ep.TLS == EmailTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("SMTP server %s does not support STARTTLS", ep.Host)
+		}
+		config, err := ep.tlsConfig()
+		if err != nil {
+			return err
+		}
+		if err := client.StartTLS(config); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if ep.Username != "" {
+		var auth smtp.Auth
+		if ep.Auth == EmailAuthLogin {
+			auth = &loginAuth{username: ep.Username, password: ep.Password, host: ep.Host}
+		} else {
+			auth = smtp.PlainAuth("", ep.Username, ep.Password, ep.Host)
+		}
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	from, _ := mail.ParseAddress(ep.From)
+	if err := client.Mail(from.Address); err != nil {
+		return fmt.Errorf("MAIL FROM rejected: %w", err)
+	}
+	for _, to := range ep.To {
+		recipient, _ := mail.ParseAddress(to)
+		if err := client.Rcpt(recipient.Address); err != nil {
+			return fmt.Errorf("recipient %s rejected: %w", recipient.Address, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA rejected: %w", err)
+	}
+	if _, err := writer.Write(body); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("email rejected: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// dial connects to the SMTP server, using TLS from the start in implicit TLS mode
+func (ep *EmailProvider) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(ep.Host, strconv.Itoa(ep.Port))
+	dialer := &net.Dialer{Timeout: ep.timeout}
+
+	var conn net.Conn
+	var err error
+	if ep.TLS == EmailTLSImplicit {
+		config, configErr := ep.tlsConfig()
+		if configErr != nil {
+			return nil, configErr
+		}
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: config}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+
+	// Bound the whole SMTP conversation
+	deadline := time.Now().Add(ep.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, ep.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SMTP handshake with %s failed: %w", address, err)
+	}
+	return client, nil
+}
+
+// tlsConfig returns the TLS configuration, trusting ca_file when set
+func (ep *EmailProvider) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{ServerName: ep.Host}
+
+	if ep.CAFile != "" {
+		pool, err := loadCAFile(ep.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
+
+// buildMessage renders a multipart/alternative email with plain-text and HTML bodies
+func (ep *EmailProvider) buildMessage(message *NotificationMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	subject, text := ep.template.Render(message, emailSubject, emailText)
+	// Keep templated subjects on one header line
+	subject = strings.Join(strings.Fields(subject), " ")
+
+	headers := []struct{ name, value string }{
+		{"From", ep.From},
+		{"To", strings.Join(ep.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", message.Timestamp.Format(time.RFC1123Z)},
+		{"Message-ID", emailMessageID(ep.From)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", writer.Boundary())},
+		{"X-ServerHealth-Level", string(message.Level)},
+	}
+	if message.AlertID != "" {
+		headers = append(headers, struct{ name, value string }{"X-ServerHealth-Alert-ID", message.AlertID})
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header.name, header.value)
+	}
+	buf.WriteString("\r\n")
+
+	// A templated body is sent as is in both parts
+	htmlBody := emailTextHTML(text)
+	if !ep.template.HasBody() {
+		var err error
+		if htmlBody, err = emailHTML(message); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", htmlBody},
+	} {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(partWriter)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// emailSubject returns the subject line for a notification
+func emailSubject(message *NotificationMessage) string {
+	return fmt.Sprintf("[%s] %s - %s", strings.ToUpper(string(message.Level)), message.Title, message.Hostname)
+}
+
+// emailMessageID returns a unique Message-ID in the sender's domain
+func emailMessageID(from string) string {
+	domain := "serverhealth.local"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(address.Address, "@"); at >= 0 {
+			domain = address.Address[at+1:]
+		}
+	}
+
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("<%d@%s>", time.Now().UnixNano(), domain)
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// emailText renders the plain-text body of a notification
+func emailText(message *NotificationMessage) string {
+	if len(message.Group) > 0 {
+		return fmt.Sprintf("%s\n\n%s\n\nServer: %s (%s)\nTime: %s\n",
+			message.Title, groupLines(message, func(s string) string { return s }),
+			message.Hostname, message.IP, message.Timestamp.Format("2006-01-02 15:04:05"))
+	}
+
+	text := fmt.Sprintf("%s\n%s\n\nServer: %s (%s)\nMetric: %s\nValue: %s\nThreshold: %s\nTime: %s\n",
+		message.Title, message.Message, message.Hostname, message.IP,
+		message.Metric, message.Value, message.Threshold, message.Timestamp.Format("2006-01-02 15:04:05"))
+	if message.AlertID != "" {
+		text += fmt.Sprintf("Alert ID: %s\n", message.AlertID)
+	}
+	return text
+}
+
+// emailHTMLTemplate renders the HTML body of a notification
+var emailHTMLTemplate = template.Must(template.New("email").Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #333;">
+<h2 style="border-left: 6px solid {{.Color}}; padding-left: 8px;">{{.Message.Title}}</h2>
+{{if .Message.Group}}<ul>
+{{range .Message.Group}}<li><strong>{{.Title}}</strong>: {{.Metric}} {{.Value}} (threshold {{.Threshold}}){{if .AlertID}} [alert {{.AlertID}}]{{end}}</li>
+{{end}}</ul>
+{{else}}<p>{{.Message.Message}}</p>
+<table cellpadding="4" style="border-collapse: collapse;">
+<tr><td><strong>Metric</strong></td><td>{{.Message.Metric}}</td></tr>
+<tr><td><strong>Value</strong></td><td>{{.Message.Value}}</td></tr>
+<tr><td><strong>Threshold</strong></td><td>{{.Message.Threshold}}</td></tr>
+{{if .Message.AlertID}}<tr><td><strong>Alert ID</strong></td><td><code>{{.Message.AlertID}}</code></td></tr>
+{{end}}</table>
+{{end}}<p style="color: #777;">Server: {{.Message.Hostname}} ({{.Message.IP}})<br>Time: {{.Time}}</p>
+</body>
+</html>
+`))
+
+// emailHTML renders the HTML body of a notification
+func emailHTML(message *NotificationMessage) (string, error) {
+	color := "#3498db" // Blue
+	switch message.Level {
+	case NotificationLevelWarning:
+		color = "#f39c12" // Orange
+	case NotificationLevelError:
+		color = "#e74c3c" // Red
+	}
+
+	var buf bytes.Buffer
+	err := emailHTMLTemplate.Execute(&buf, struct {
+		Message *NotificationMessage
+		Color   string
+		Time    string
+	}{message, color, message.Timestamp.Format("2006-01-02 15:04:05")})
+	if err != nil {
+		return "", fmt.Errorf("failed to render HTML body: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// emailTextHTML wraps a plain-text body in HTML, keeping its line breaks
+func emailTextHTML(text string) string {
+	return fmt.Sprintf("<!DOCTYPE html>\n<html>\n<body style=\"font-family: sans-serif; color: #333; white-space: pre-wrap;\">%s</body>\n</html>\n",
+		template.HTMLEscapeString(text))
+}
+
+// loginAuth implements the SMTP LOGIN authentication mechanism
+type loginAuth struct {
+	username string
+	password string
+	host     string
+}
+
+// Start begins LOGIN authentication, refusing to send credentials in the clear
+// except to localhost
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	if server.Name != a.host {
+		return "", nil, errors.New("wrong host name")
+	}
+	return "LOGIN", nil, nil
+}
+
+// Next answers the server's username and password challenges
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(string(fromServer))) {
+	case "username:":
+		return []byte(a.username), nil
+	case "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected LOGIN challenge %q", fromServer)
+	}
+}
+
+// isLocalhost reports whether host refers to the local machine
+func isLocalhost(host string) bool {
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
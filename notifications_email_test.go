@@ -0,0 +1,239 @@
+package main
+
+import (
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpSession is what a fakeSMTPServer received during one connection
+type smtpSession struct {
+	tls   bool
+	auth  string
+	from  string
+	rcpts []string
+	data  string
+}
+
+// fakeSMTPServer is a minimal SMTP server that accepts one connection per
+// message and rejects the recipients in reject
+type fakeSMTPServer struct {
+	listener net.Listener
+	tls      *tls.Config
+	mode     string
+	reject   map[string]bool
+	sessions chan smtpSession
+}
+
+// newFakeSMTPServer starts a server for the given email TLS mode. Its
+// certificate is valid for 127.0.0.1 and signed by the CA written to caFile.
+func newFakeSMTPServer(t *testing.T, mode string, reject ...string) (server *fakeSMTPServer, caFile string) {
+	t.Helper()
+
+	// Borrow the certificate of an httptest TLS server
+	httpServer := httptest.NewTLSServer(nil)
+	tlsConfig := &tls.Config{Certificates: httpServer.TLS.Certificates}
+	caFile = filepath.Join(t.TempDir(), "ca.pem")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: httpServer.Certificate().Raw})
+	httpServer.Close()
+	if err := os.WriteFile(caFile, ca, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server = &fakeSMTPServer{
+		listener: listener,
+		tls:      tlsConfig,
+		mode:     mode,
+		reject:   make(map[string]bool),
+		sessions: make(chan smtpSession, 1),
+	}
+	for _, address := range reject {
+		server.reject[address] = true
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go server.serve()
+	return server, caFile
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.sessions <- s.handle(conn)
+	}
+}
+
+// handle runs one SMTP conversation and returns what was received
+func (s *fakeSMTPServer) handle(conn net.Conn) smtpSession {
+	defer func() { conn.Close() }()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	var session smtpSession
+	if s.mode == EmailTLSImplicit {
+		conn = tls.Server(conn, s.tls)
+		session.tls = true
+	}
+	text := textproto.NewConn(conn)
+	text.PrintfLine("220 fake ESMTP")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return session
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			extensions := []string{"fake", "8BITMIME", "AUTH PLAIN LOGIN"}
+			if s.mode == EmailTLSStartTLS && !session.tls {
+				extensions = append(extensions, "STARTTLS")
+			}
+			for i, extension := range extensions {
+				separator := "-"
+				if i == len(extensions)-1 {
+					separator = " "
+				}
+				text.PrintfLine("250%s%s", separator, extension)
+			}
+		case "STARTTLS":
+			text.PrintfLine("220 ready to start TLS")
+			tlsConn := tls.Server(conn, s.tls)
+			if err := tlsConn.Handshake(); err != nil {
+				return session
+			}
+			conn = tlsConn
+			text = textproto.NewConn(conn)
+			session.tls = true
+		case "AUTH":
+			mechanism, initial, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(initial)
+			session.auth = mechanism + " " + string(decoded)
+			text.PrintfLine("235 authenticated")
+		case "MAIL":
+			session.from, _, _ = strings.Cut(strings.TrimPrefix(arg, "FROM:<"), ">")
+			text.PrintfLine("250 ok")
+		case "RCPT":
+			rcpt, _, _ := strings.Cut(strings.TrimPrefix(arg, "TO:<"), ">")
+			if s.reject[rcpt] {
+				text.PrintfLine("550 no such user %s", rcpt)
+				continue
+			}
+			session.rcpts = append(session.rcpts, rcpt)
+			text.PrintfLine("250 ok")
+		case "DATA":
+			text.PrintfLine("354 go ahead")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return session
+			}
+			session.data = string(data)
+			text.PrintfLine("250 queued")
+		case "QUIT":
+			text.PrintfLine("221 bye")
+			return session
+		default:
+			text.PrintfLine("502 %s not implemented", verb)
+		}
+	}
+}
+
+func TestEmailProviderSMTP(t *testing.T) {
+	recipients := []string{"ops@example.com", "Oncall <oncall@example.com>"}
+
+	tests := []struct {
+		name      string
+		mode      string
+		username  string
+		withCA    bool
+		reject    []string
+		wantErr   string
+		wantAuth  string
+		wantRcpts []string
+	}{
+		{name: "plain SMTP", mode: EmailTLSNone, wantRcpts: []string{"ops@example.com", "oncall@example.com"}},
+		{name: "plain SMTP to localhost with auth", mode: EmailTLSNone, username: "alerts",
+			wantAuth: "PLAIN \x00alerts\x00secret", wantRcpts: []string{"ops@example.com", "oncall@example.com"}},
+		{name: "STARTTLS with auth", mode: EmailTLSStartTLS, username: "alerts", withCA: true,
+			wantAuth: "PLAIN \x00alerts\x00secret", wantRcpts: []string{"ops@example.com", "oncall@example.com"}},
+		{name: "implicit TLS", mode: EmailTLSImplicit, withCA: true, wantRcpts: []string{"ops@example.com", "oncall@example.com"}},
+		{name: "STARTTLS with untrusted certificate", mode: EmailTLSStartTLS, wantErr: "STARTTLS failed"},
+		{name: "recipient rejected", mode: EmailTLSNone, reject: []string{"oncall@example.com"},
+			wantErr: "recipient oncall@example.com rejected: 550"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, caFile := newFakeSMTPServer(t, tt.mode, tt.reject...)
+			if !tt.withCA {
+				caFile = ""
+			}
+			password := ""
+			if tt.username != "" {
+				password = "secret"
+			}
+
+			provider := NewEmailProvider("127.0.0.1", server.port(), "ServerHealth <alerts@example.com>", recipients,
+				tt.username, password, tt.mode, "", caFile, TemplateConfig{})
+			if err := provider.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			err := provider.Send(t.Context(), testMessage())
+			session := <-server.sessions
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Send() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			if session.tls != (tt.mode != EmailTLSNone) {
+				t.Errorf("TLS = %v for mode %s", session.tls, tt.mode)
+			}
+			if session.auth != tt.wantAuth {
+				t.Errorf("AUTH = %q, want %q", session.auth, tt.wantAuth)
+			}
+			if session.from != "alerts@example.com" {
+				t.Errorf("MAIL FROM = %q", session.from)
+			}
+			if fmt.Sprint(session.rcpts) != fmt.Sprint(tt.wantRcpts) {
+				t.Errorf("RCPT TO = %v, want %v", session.rcpts, tt.wantRcpts)
+			}
+			if !strings.Contains(session.data, "Subject: [ERROR] Disk usage critical - web-1") {
+				t.Errorf("message data = %q", session.data)
+			}
+		})
+	}
+}
+
+func TestEmailProviderRejectsCAFileWithoutTLS(t *testing.T) {
+	provider := NewEmailProvider("127.0.0.1", 25, "alerts@example.com", []string{"ops@example.com"},
+		"", "", EmailTLSNone, "", "/etc/ssl/ca.pem", TemplateConfig{})
+	if err := provider.Validate(); err == nil || !strings.Contains(err.Error(), "only used with starttls or tls") {
+		t.Fatalf("Validate() error = %v", err)
+	}
+}
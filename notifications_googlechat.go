@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GoogleChatProvider implements NotificationProvider for Google Chat space webhooks
+type GoogleChatProvider struct {
+	WebhookURL string
+	Template   TemplateConfig
+	template   *MessageTemplate
+	client     *http.Client
+}
+
+// NewGoogleChatProvider creates a new Google Chat notification provider.
+// Templates replace the card title and text.
+func NewGoogleChatProvider(webhookURL string, tmpl TemplateConfig, client *http.Client) *GoogleChatProvider {
+	return &GoogleChatProvider{
+		WebhookURL: webhookURL,
+		Template:   tmpl,
+		client:     client,
+	}
+}
+
+// Validate validates the Google Chat provider configuration
+func (gp *GoogleChatProvider) Validate() error {
+	if gp.WebhookURL == "" {
+		return fmt.Errorf("webhook URL is required")
+	}
+
+	parsed, err := url.Parse(gp.WebhookURL)
+	if err != nil || parsed.Scheme != "https" {
+		return fmt.Errorf("webhook URL must use HTTPS")
+	}
+
+	if parsed.Host != "chat.googleapis.com" {
+		return fmt.Errorf("webhook URL must be from chat.googleapis.com")
+	}
+
+	tmpl, err := gp.Template.Load()
+	if err != nil {
+		return err
+	}
+	gp.template = tmpl
+
+	return nil
+}
+
+// GetType returns the notification type
+func (gp *GoogleChatProvider) GetType() NotificationType {
+	return NotificationTypeGoogleChat
+}
+
+// Send sends a notification to Google Chat as a cards v2 message
+func (gp *GoogleChatProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	title, text := gp.template.Render(message, emojiTitle, alertText)
+
+	summary := map[string]interface{}{
+		"widgets": []map[string]interface{}{
+			googleChatText(fmt.Sprintf("%s<br>%s",
+				googleChatLevel(message.Level), html.EscapeString(text))),
+		},
+	}
+	sections := []map[string]interface{}{summary}
+
+	if len(message.Group) > 0 {
+		// Grouped alerts get a section each
+		sections = append(sections, googleChatFacts("", [][2]string{
+			{"Server", fmt.Sprintf("%s (%s)", message.Hostname, message.IP)},
+		}))
+		for _, alert := range message.Group {
+			sections = append(sections, googleChatFacts(
+				fmt.Sprintf("%s %s", levelEmoji(alert.Level), alert.Title), alertFacts(alert, false)))
+		}
+	} else {
+		sections = append(sections, googleChatFacts("", alertFacts(message, true)))
+	}
+
+	payload := map[string]interface{}{
+		// Shown in notifications and clients that cannot render cards
+		"text": title,
+		"cardsV2": []map[string]interface{}{
+			{
+				"cardId": "serverhealth-alert",
+				"card": map[string]interface{}{
+					"header": map[string]interface{}{
+						"title":    title,
+						"subtitle": fmt.Sprintf("%s • %s", message.Hostname, message.Timestamp.Format(time.RFC1123)),
+					},
+					"sections": sections,
+				},
+			},
+		},
+	}
+
+	return sendHTTPRequest(ctx, gp.client, gp.WebhookURL, payload)
+}
+
+// googleChatLevel renders the notification level in its colour
+func googleChatLevel(level NotificationLevel) string {
+	return fmt.Sprintf(`<font color="#%06x"><b>%s</b></font>`, levelColor(level), strings.ToUpper(string(level)))
+}
+
+// googleChatText builds a text paragraph widget
+func googleChatText(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"textParagraph": map[string]interface{}{"text": text},
+	}
+}
+
+// googleChatFacts builds a card section with a decorated text widget per fact
+func googleChatFacts(header string, facts [][2]string) map[string]interface{} {
+	widgets := make([]map[string]interface{}, 0, len(facts))
+	for _, fact := range facts {
+		widgets = append(widgets, map[string]interface{}{
+			"decoratedText": map[string]interface{}{
+				"topLabel": fact[0],
+				"text":     html.EscapeString(fact[1]),
+			},
+		})
+	}
+
+	section := map[string]interface{}{"widgets": widgets}
+	if header != "" {
+		section["header"] = html.EscapeString(header)
+	}
+	return section
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// gotifyMessage is a Gotify create message request
+type gotifyMessage struct {
+	Title    string                 `json:"title"`
+	Message  string                 `json:"message"`
+	Priority int                    `json:"priority"`
+	Extras   map[string]interface{} `json:"extras,omitempty"`
+}
+
+// GotifyProvider implements NotificationProvider for Gotify servers
+type GotifyProvider struct {
+	ServerURL string
+	Token     string
+	Template  TemplateConfig
+	template  *MessageTemplate
+	client    *http.Client
+}
+
+// NewGotifyProvider creates a new Gotify notification provider. The token is
+// the application token created in the Gotify web UI.
+func NewGotifyProvider(serverURL, token string, tmpl TemplateConfig, client *http.Client) *GotifyProvider {
+	return &GotifyProvider{
+		ServerURL: strings.TrimRight(serverURL, "/"),
+		Token:     token,
+		Template:  tmpl,
+		client:    client,
+	}
+}
+
+// Validate validates the Gotify provider configuration
+func (gp *GotifyProvider) Validate() error {
+	if gp.ServerURL == "" {
+		return fmt.Errorf("server URL is required")
+	}
+
+	parsed, err := url.Parse(gp.ServerURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("server URL must be an http:// or https:// URL")
+	}
+
+	if gp.Token == "" {
+		return fmt.Errorf("application token is required")
+	}
+
+	tmpl, err := gp.Template.Load()
+	if err != nil {
+		return err
+	}
+	gp.template = tmpl
+
+	return nil
+}
+
+// GetType returns the notification type
+func (gp *GotifyProvider) GetType() NotificationType {
+	return NotificationTypeGotify
+}
+
+// Send sends a notification to the Gotify application
+func (gp *GotifyProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	title, text := gp.template.Render(message, gotifyTitle, pushText)
+	body, err := json.Marshal(gotifyMessage{
+		Title:    title,
+		Message:  text,
+		Priority: gotifyPriority(message.Level),
+		Extras: map[string]interface{}{
+			"client::display": map[string]string{"contentType": "text/plain"},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	return sendHTTP(ctx, gp.client, httpRequest{
+		Method: http.MethodPost,
+		URL:    gp.ServerURL + "/message",
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			"X-Gotify-Key": gp.Token,
+		},
+		Body: body,
+	})
+}
+
+// gotifyTitle is the default title layout of Gotify messages
+func gotifyTitle(message *NotificationMessage) string {
+	return fmt.Sprintf("%s %s", levelEmoji(message.Level), hostTitle(message))
+}
+
+// gotifyPriority maps a notification level to a Gotify priority (0-10). The
+// Gotify Android app plays a sound from priority 4 and pops up from 8.
+func gotifyPriority(level NotificationLevel) int {
+	switch level {
+	case NotificationLevelError:
+		return 8
+	case NotificationLevelWarning:
+		return 5
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// defaultJournaldSocket is the socket of the systemd journal native protocol
+const defaultJournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldProvider implements NotificationProvider by writing each alert to the
+// systemd journal with SERVERHEALTH_* fields, e.g. for journalctl SERVERHEALTH_CHECK=disk
+type JournaldProvider struct {
+	Socket   string
+	Fields   map[string]string
+	Template TemplateConfig
+	template *MessageTemplate
+}
+
+// NewJournaldProvider creates a new journald notification provider. Fields are
+// added to every entry; an empty socket uses the systemd journal socket.
+// Templates replace the MESSAGE field.
+func NewJournaldProvider(socket string, fields map[string]string, tmpl TemplateConfig) *JournaldProvider {
+	if socket == "" {
+		socket = defaultJournaldSocket
+	}
+
+	return &JournaldProvider{
+		Socket:   socket,
+		Fields:   fields,
+		Template: tmpl,
+	}
+}
+
+// Validate validates the journald provider configuration
+func (jp *JournaldProvider) Validate() error {
+	if _, err := os.Stat(jp.Socket); err != nil {
+		return fmt.Errorf("journald socket %s not found; is systemd-journald running?", jp.Socket)
+	}
+
+	for name := range jp.Fields {
+		if !isJournalFieldName(name) {
+			return fmt.Errorf("invalid journal field name %q: use upper case letters, digits and underscores, not starting with an underscore", name)
+		}
+	}
+
+	tmpl, err := jp.Template.Load()
+	if err != nil {
+		return err
+	}
+	jp.template = tmpl
+
+	return nil
+}
+
+// GetType returns the notification type
+func (jp *JournaldProvider) GetType() NotificationType {
+	return NotificationTypeJournald
+}
+
+// Send writes one journal entry per alert in the message
+func (jp *JournaldProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	alerts := []*NotificationMessage{message}
+	if len(message.Group) > 0 {
+		alerts = message.Group
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "unixgram", jp.Socket)
+	if err != nil {
+		return fmt.Errorf("failed to connect to journald: %w", err)
+	}
+	defer conn.Close()
+
+	for _, alert := range alerts {
+		if _, err := conn.Write(jp.entry(alert)); err != nil {
+			return fmt.Errorf("failed to write journal entry: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// entry encodes an alert in the journal native protocol
+func (jp *JournaldProvider) entry(message *NotificationMessage) []byte {
+	title, body := jp.template.Render(message, alertTitle, alertText)
+
+	fields := [][2]string{
+		{"MESSAGE", title + ": " + body},
+		{"PRIORITY", strconv.Itoa(syslogSeverity(message.Level))},
+		{"SYSLOG_IDENTIFIER", appName},
+		{"SERVERHEALTH_LEVEL", string(message.Level)},
+		{"SERVERHEALTH_TITLE", message.Title},
+		{"SERVERHEALTH_HOSTNAME", message.Hostname},
+		{"SERVERHEALTH_IP", message.IP},
+		{"SERVERHEALTH_METRIC", message.Metric},
+		{"SERVERHEALTH_VALUE", message.Value},
+		{"SERVERHEALTH_THRESHOLD", message.Threshold},
+		{"SERVERHEALTH_CHECK", message.Check},
+		{"SERVERHEALTH_ALERT_ID", message.AlertID},
+	}
+
+	for _, key := range sortedKeys(message.Labels) {
+		fields = append(fields, [2]string{"SERVERHEALTH_LABEL_" + upperSnake(key), message.Labels[key]})
+	}
+	for _, key := range sortedKeys(jp.Fields) {
+		fields = append(fields, [2]string{key, jp.Fields[key]})
+	}
+
+	var b bytes.Buffer
+	for _, field := range fields {
+		if field[1] == "" {
+			continue
+		}
+
+		// Values containing newlines are sent with an explicit length
+		if strings.Contains(field[1], "\n") {
+			b.WriteString(field[0] + "\n")
+			binary.Write(&b, binary.LittleEndian, uint64(len(field[1])))
+			b.WriteString(field[1] + "\n")
+			continue
+		}
+		b.WriteString(field[0] + "=" + field[1] + "\n")
+	}
+	return b.Bytes()
+}
+
+// isJournalFieldName reports whether name is a valid user journal field name
+func isJournalFieldName(name string) bool {
+	if name == "" || len(name) > 64 || name[0] == '_' || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	return upperSnake(name) == name
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// matrixMessage is the content of an m.room.message event
+type matrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format"`
+	FormattedBody string `json:"formatted_body"`
+}
+
+// MatrixProvider implements NotificationProvider for Matrix rooms via the
+// Client-Server API
+type MatrixProvider struct {
+	HomeserverURL string
+	AccessToken   string
+	RoomID        string
+	Template      TemplateConfig
+	template      *MessageTemplate
+	client        *http.Client
+}
+
+// NewMatrixProvider creates a new Matrix notification provider. Templates
+// replace the message heading and text.
+func NewMatrixProvider(homeserverURL, accessToken, roomID string, tmpl TemplateConfig, client *http.Client) *MatrixProvider {
+	return &MatrixProvider{
+		HomeserverURL: strings.TrimRight(homeserverURL, "/"),
+		AccessToken:   accessToken,
+		RoomID:        roomID,
+		Template:      tmpl,
+		client:        client,
+	}
+}
+
+// Validate validates the Matrix provider configuration
+func (mp *MatrixProvider) Validate() error {
+	if mp.HomeserverURL == "" {
+		return fmt.Errorf("homeserver URL is required")
+	}
+
+	parsed, err := url.Parse(mp.HomeserverURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("homeserver URL must be an http:// or https:// URL")
+	}
+
+	if mp.AccessToken == "" {
+		return fmt.Errorf("access token is required")
+	}
+
+	// Messages can only be sent to room IDs, not aliases such as #ops:example.org
+	if !strings.HasPrefix(mp.RoomID, "!") || !strings.Contains(mp.RoomID, ":") {
+		return fmt.Errorf("room ID must look like !abcdef:example.org")
+	}
+
+	tmpl, err := mp.Template.Load()
+	if err != nil {
+		return err
+	}
+	mp.template = tmpl
+
+	return nil
+}
+
+// GetType returns the notification type
+func (mp *MatrixProvider) GetType() NotificationType {
+	return NotificationTypeMatrix
+}
+
+// Send posts a notification to the Matrix room. The transaction ID is chosen
+// once per notification, so the homeserver drops retried duplicates.
+func (mp *MatrixProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	title, text := mp.template.Render(message, emojiTitle, matrixText)
+
+	// Without a template the HTML body shows the alert's fields as a list
+	formatted := matrixHTML(message)
+	if mp.template != nil {
+		formatted = matrixHeading(message.Level, title) +
+			"<p>" + strings.ReplaceAll(html.EscapeString(text), "\n", "<br>") + "</p>"
+	}
+
+	body, err := json.Marshal(matrixMessage{
+		MsgType:       "m.text",
+		Body:          title + "\n" + text,
+		Format:        "org.matrix.custom.html",
+		FormattedBody: formatted,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	txnID := fmt.Sprintf("%s-%d-%s", appName, time.Now().UnixNano(), newAlertID())
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		mp.HomeserverURL, url.PathEscape(mp.RoomID), url.PathEscape(txnID))
+
+	return sendHTTP(ctx, mp.client, httpRequest{
+		Method: http.MethodPut,
+		URL:    endpoint,
+		Headers: map[string]string{
+			"Content-Type":  "application/json",
+			"Authorization": "Bearer " + mp.AccessToken,
+		},
+		Body: body,
+	})
+}
+
+// matrixText renders the plain-text body of a notification below its heading
+func matrixText(message *NotificationMessage) string {
+	return fmt.Sprintf("%s\nServer: %s (%s)\nTime: %s",
+		pushText(message), message.Hostname, message.IP, message.Timestamp.Format("2006-01-02 15:04:05"))
+}
+
+// matrixHeading renders a heading in the level's colour
+func matrixHeading(level NotificationLevel, title string) string {
+	return fmt.Sprintf(`<h4><font color="#%06x">%s</font></h4>`, levelColor(level), html.EscapeString(title))
+}
+
+// matrixHTML renders the HTML body of a notification in the level's colour
+func matrixHTML(message *NotificationMessage) string {
+	var b strings.Builder
+	b.WriteString(matrixHeading(message.Level, emojiTitle(message)))
+
+	if len(message.Group) > 0 {
+		b.WriteString("<ul>")
+		for _, alert := range message.Group {
+			fmt.Fprintf(&b, "<li>%s <b>%s</b>: %s %s (threshold %s)</li>", levelEmoji(alert.Level),
+				html.EscapeString(alert.Title), html.EscapeString(alert.Metric),
+				html.EscapeString(alert.Value), html.EscapeString(alert.Threshold))
+		}
+		b.WriteString("</ul>")
+	} else {
+		fmt.Fprintf(&b, "<p>%s</p><ul>", html.EscapeString(message.Message))
+		for _, fact := range alertFacts(message, false) {
+			fmt.Fprintf(&b, "<li><b>%s:</b> %s</li>", fact[0], html.EscapeString(fact[1]))
+		}
+		b.WriteString("</ul>")
+	}
+
+	fmt.Fprintf(&b, "<p><i>Server: %s (%s) • %s</i></p>", html.EscapeString(message.Hostname),
+		html.EscapeString(message.IP), message.Timestamp.Format("2006-01-02 15:04:05"))
+	return b.String()
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ntfyMessage is an ntfy JSON publish request
+type ntfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// NtfyProvider implements NotificationProvider for ntfy topics
+type NtfyProvider struct {
+	TopicURL string
+	Token    string
+	Template TemplateConfig
+	template *MessageTemplate
+	client   *http.Client
+}
+
+// NewNtfyProvider creates a new ntfy notification provider for a topic URL such
+// as https://ntfy.sh/my-alerts. The optional token is sent as a bearer token.
+func NewNtfyProvider(topicURL, token string, tmpl TemplateConfig, client *http.Client) *NtfyProvider {
+	return &NtfyProvider{
+		TopicURL: topicURL,
+		Token:    token,
+		Template: tmpl,
+		client:   client,
+	}
+}
+
+// Validate validates the ntfy provider configuration
+func (np *NtfyProvider) Validate() error {
+	if np.TopicURL == "" {
+		return fmt.Errorf("topic URL is required")
+	}
+
+	if _, _, err := np.splitTopicURL(); err != nil {
+		return err
+	}
+
+	tmpl, err := np.Template.Load()
+	if err != nil {
+		return err
+	}
+	np.template = tmpl
+
+	return nil
+}
+
+// GetType returns the notification type
+func (np *NtfyProvider) GetType() NotificationType {
+	return NotificationTypeNtfy
+}
+
+// Send publishes a notification to the ntfy topic
+func (np *NtfyProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	server, topic, err := np.splitTopicURL()
+	if err != nil {
+		return err
+	}
+
+	title, text := np.template.Render(message, hostTitle, pushText)
+	body, err := json.Marshal(ntfyMessage{
+		Topic:    topic,
+		Title:    title,
+		Message:  text,
+		Priority: ntfyPriority(message.Level),
+		Tags:     ntfyTags(message.Level),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	if np.Token != "" {
+		headers["Authorization"] = "Bearer " + np.Token
+	}
+
+	// JSON messages are published to the server root with the topic in the body
+	return sendHTTP(ctx, np.client, httpRequest{
+		Method:  http.MethodPost,
+		URL:     server,
+		Headers: headers,
+		Body:    body,
+	})
+}
+
+// splitTopicURL splits the topic URL into the server URL and the topic name
+func (np *NtfyProvider) splitTopicURL() (server, topic string, err error) {
+	parsed, err := url.Parse(np.TopicURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return "", "", fmt.Errorf("topic URL must be an http:// or https:// URL")
+	}
+
+	path := strings.Trim(parsed.Path, "/")
+	index := strings.LastIndex(path, "/")
+	topic = path[index+1:]
+	if topic == "" {
+		return "", "", fmt.Errorf("topic URL must end with the topic name, e.g. https://ntfy.sh/my-alerts")
+	}
+
+	parsed.Path = "/" + path[:index+1]
+	parsed.RawQuery = ""
+	return parsed.String(), topic, nil
+}
+
+// ntfyPriority maps a notification level to an ntfy priority (1-5)
+func ntfyPriority(level NotificationLevel) int {
+	switch level {
+	case NotificationLevelError:
+		return 5
+	case NotificationLevelWarning:
+		return 4
+	default:
+		return 3
+	}
+}
+
+// ntfyTags returns the ntfy tags for a notification level; tags matching an
+// emoji short code are shown as that emoji
+func ntfyTags(level NotificationLevel) []string {
+	switch level {
+	case NotificationLevelError:
+		return []string{"rotating_light", appName}
+	case NotificationLevelWarning:
+		return []string{"warning", appName}
+	default:
+		return []string{"information_source", appName}
+	}
+}
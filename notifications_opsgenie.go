@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// opsgenieUSURL and opsgenieEUURL are the Opsgenie API base URLs per region
+	opsgenieUSURL = "https://api.opsgenie.com"
+	opsgenieEUURL = "https://api.eu.opsgenie.com"
+
+	// Field limits of the Opsgenie Alerts API
+	opsgenieMaxMessage     = 130
+	opsgenieMaxDescription = 15000
+	opsgenieMaxTag         = 50
+	opsgenieMaxTags        = 20
+)
+
+// opsgenieAlert is an Opsgenie create alert request
+type opsgenieAlert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Entity      string            `json:"entity,omitempty"`
+	Source      string            `json:"source,omitempty"`
+	Priority    string            `json:"priority"`
+}
+
+// opsgenieClose is an Opsgenie close alert request
+type opsgenieClose struct {
+	Source string `json:"source,omitempty"`
+	Note   string `json:"note,omitempty"`
+}
+
+// OpsgenieProvider implements NotificationProvider for the Opsgenie Alerts API
+type OpsgenieProvider struct {
+	APIKey  string
+	BaseURL string
+	client  *http.Client
+}
+
+// NewOpsgenieProvider creates a new Opsgenie notification provider. The API base
+// URL defaults to the region's endpoint ("us" or "eu"); baseURL overrides it.
+func NewOpsgenieProvider(apiKey, region, baseURL string, client *http.Client) *OpsgenieProvider {
+	if baseURL == "" {
+		baseURL = opsgenieUSURL
+		if strings.EqualFold(region, "eu") {
+			baseURL = opsgenieEUURL
+		}
+	}
+
+	return &OpsgenieProvider{
+		APIKey:  apiKey,
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}
+}
+
+// Validate validates the Opsgenie provider configuration
+func (op *OpsgenieProvider) Validate() error {
+	if op.APIKey == "" {
+		return fmt.Errorf("API key is required")
+	}
+
+	parsed, err := url.Parse(op.BaseURL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("Opsgenie URL must be an http:// or https:// URL")
+	}
+
+	return nil
+}
+
+// GetType returns the notification type
+func (op *OpsgenieProvider) GetType() NotificationType {
+	return NotificationTypeOpsgenie
+}
+
+// Send creates an Opsgenie alert for each alert in the message. Opsgenie
+// deduplicates open alerts by alias. Informational messages are not sent.
+func (op *OpsgenieProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	alerts := []*NotificationMessage{message}
+	if len(message.Group) > 0 {
+		alerts = message.Group
+	}
+
+	for _, alert := range alerts {
+		if !isAlert(alert) {
+			continue
+		}
+		if err := op.post(ctx, "/v2/alerts", op.createAlert(alert)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Resolve closes the Opsgenie alert opened for the message's check
+func (op *OpsgenieProvider) Resolve(ctx context.Context, message *NotificationMessage) error {
+	path := fmt.Sprintf("/v2/alerts/%s/close?identifierType=alias", url.PathEscape(incidentKey(message)))
+	return op.post(ctx, path, opsgenieClose{
+		Source: appName,
+		Note:   fmt.Sprintf("%s recovered on %s", message.Check, message.Hostname),
+	})
+}
+
+// post sends a JSON request to the Opsgenie API
+func (op *OpsgenieProvider) post(ctx context.Context, path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	return sendHTTP(ctx, op.client, httpRequest{
+		Method: http.MethodPost,
+		URL:    op.BaseURL + path,
+		Headers: map[string]string{
+			"Content-Type":  "application/json",
+			"Authorization": "GenieKey " + op.APIKey,
+		},
+		Body: body,
+	})
+}
+
+// createAlert builds the create alert request for a single alert
+func (op *OpsgenieProvider) createAlert(message *NotificationMessage) opsgenieAlert {
+	description := fmt.Sprintf("%s\n\nServer: %s (%s)\nMetric: %s\nValue: %s\nThreshold: %s",
+		message.Message, message.Hostname, message.IP, message.Metric, message.Value, message.Threshold)
+
+	return opsgenieAlert{
+		Message:     truncate(fmt.Sprintf("%s on %s", message.Title, message.Hostname), opsgenieMaxMessage),
+		Alias:       incidentKey(message),
+		Description: truncate(description, opsgenieMaxDescription),
+		Tags:        opsgenieTags(message.Labels),
+		Details: map[string]string{
+			"metric":    message.Metric,
+			"value":     message.Value,
+			"threshold": message.Threshold,
+			"check":     message.Check,
+			"alert_id":  message.AlertID,
+		},
+		Entity:   message.Hostname,
+		Source:   appName,
+		Priority: opsgeniePriority(message.Level),
+	}
+}
+
+// opsgenieTags turns labels into sorted "key:value" tags within Opsgenie's limits
+func opsgenieTags(labels map[string]string) []string {
+	tags := make([]string, 0, len(labels))
+	for key, value := range labels {
+		tags = append(tags, truncate(key+":"+value, opsgenieMaxTag))
+	}
+	sort.Strings(tags)
+
+	if len(tags) > opsgenieMaxTags {
+		tags = tags[:opsgenieMaxTags]
+	}
+	return tags
+}
+
+// opsgeniePriority maps a notification level to an Opsgenie priority
+func opsgeniePriority(level NotificationLevel) string {
+	switch level {
+	case NotificationLevelError:
+		return "P1"
+	case NotificationLevelWarning:
+		return "P3"
+	default:
+		return "P5"
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestOpsgenieCreateAndClose(t *testing.T) {
+	server := newRequestRecorder(t)
+	provider := NewOpsgenieProvider("genie-key", "eu", server.URL+"/", server.Client())
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	alert := testMessage()
+	if err := provider.Send(t.Context(), alert); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := provider.Resolve(t.Context(), alert); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	requests := server.received()
+	if len(requests) != 2 {
+		t.Fatalf("received %d requests, want create and close", len(requests))
+	}
+	for _, request := range requests {
+		if got := request.Header.Get("Authorization"); got != "GenieKey genie-key" {
+			t.Errorf("%s Authorization = %q", request.Path, got)
+		}
+		if request.Method != http.MethodPost {
+			t.Errorf("%s sent with %s", request.Path, request.Method)
+		}
+	}
+
+	alias := "serverhealth/web-1/disk"
+	create := requests[0]
+	if create.Path != "/v2/alerts" || create.Query != "" {
+		t.Errorf("create sent to %s?%s", create.Path, create.Query)
+	}
+	var created opsgenieAlert
+	if err := json.Unmarshal(create.Body, &created); err != nil {
+		t.Fatalf("create body %s: %v", create.Body, err)
+	}
+	if created.Alias != alias || created.Priority != "P1" || created.Entity != "web-1" {
+		t.Errorf("created alert = %+v", created)
+	}
+
+	// The alias contains slashes, so it must be escaped into a single path segment
+	closeRequest := requests[1]
+	if want := "/v2/alerts/" + url.PathEscape(alias) + "/close"; closeRequest.Path != want {
+		t.Errorf("close path = %s, want %s", closeRequest.Path, want)
+	}
+	if query, _ := url.ParseQuery(closeRequest.Query); query.Get("identifierType") != "alias" {
+		t.Errorf("close query = %q, want identifierType=alias", closeRequest.Query)
+	}
+	var closed opsgenieClose
+	if err := json.Unmarshal(closeRequest.Body, &closed); err != nil || closed.Source != appName {
+		t.Errorf("close body = %s, %v", closeRequest.Body, err)
+	}
+}
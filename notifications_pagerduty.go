@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// defaultPagerDutyURL is the PagerDuty Events API v2 endpoint
+	defaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+	// pagerDutyMaxSummary is the longest summary PagerDuty accepts
+	pagerDutyMaxSummary = 1024
+)
+
+// pagerDutyEvent is an Events API v2 request
+type pagerDutyEvent struct {
+	RoutingKey  string            `json:"routing_key"`
+	EventAction string            `json:"event_action"`
+	DedupKey    string            `json:"dedup_key"`
+	Client      string            `json:"client,omitempty"`
+	Payload     *pagerDutyPayload `json:"payload,omitempty"`
+}
+
+// pagerDutyPayload describes the event that triggered an alert
+type pagerDutyPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	Component     string                 `json:"component,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+// PagerDutyProvider implements NotificationProvider for the PagerDuty Events API v2
+type PagerDutyProvider struct {
+	RoutingKey string
+	URL        string
+	client     *http.Client
+}
+
+// NewPagerDutyProvider creates a new PagerDuty notification provider.
+// An empty endpoint URL uses the PagerDuty Events API.
+func NewPagerDutyProvider(routingKey, endpoint string, client *http.Client) *PagerDutyProvider {
+	if endpoint == "" {
+		endpoint = defaultPagerDutyURL
+	}
+
+	return &PagerDutyProvider{
+		RoutingKey: routingKey,
+		URL:        endpoint,
+		client:     client,
+	}
+}
+
+// Validate validates the PagerDuty provider configuration
+func (pp *PagerDutyProvider) Validate() error {
+	if pp.RoutingKey == "" {
+		return fmt.Errorf("routing key is required")
+	}
+
+	parsed, err := url.Parse(pp.URL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("PagerDuty URL must be an http:// or https:// URL")
+	}
+
+	return nil
+}
+
+// GetType returns the notification type
+func (pp *PagerDutyProvider) GetType() NotificationType {
+	return NotificationTypePagerDuty
+}
+
+// Send triggers a PagerDuty event for each alert in the message. Informational
+// messages such as digests are not alerts and are not sent, so they never page.
+func (pp *PagerDutyProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	alerts := []*NotificationMessage{message}
+	if len(message.Group) > 0 {
+		alerts = message.Group
+	}
+
+	for _, alert := range alerts {
+		if !isAlert(alert) {
+			continue
+		}
+		if err := sendHTTPRequest(ctx, pp.client, pp.URL, pp.triggerEvent(alert)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Resolve resolves the PagerDuty incident opened for the message's check.
+// PagerDuty ignores resolve events for incidents that are not open.
+func (pp *PagerDutyProvider) Resolve(ctx context.Context, message *NotificationMessage) error {
+	return sendHTTPRequest(ctx, pp.client, pp.URL, pagerDutyEvent{
+		RoutingKey:  pp.RoutingKey,
+		EventAction: "resolve",
+		DedupKey:    incidentKey(message),
+		Client:      appName,
+	})
+}
+
+// triggerEvent builds the trigger event for a single alert
+func (pp *PagerDutyProvider) triggerEvent(message *NotificationMessage) pagerDutyEvent {
+	summary := truncate(fmt.Sprintf("%s on %s: %s", message.Title, message.Hostname, message.Message), pagerDutyMaxSummary)
+
+	details := map[string]interface{}{
+		"message":   message.Message,
+		"value":     message.Value,
+		"threshold": message.Threshold,
+		"ip":        message.IP,
+		"alert_id":  message.AlertID,
+	}
+	if len(message.Labels) > 0 {
+		details["labels"] = message.Labels
+	}
+
+	return pagerDutyEvent{
+		RoutingKey:  pp.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    incidentKey(message),
+		Client:      appName,
+		Payload: &pagerDutyPayload{
+			Summary:       summary,
+			Source:        message.Hostname,
+			Severity:      pagerDutySeverity(message.Level),
+			Timestamp:     message.Timestamp.Format(time.RFC3339),
+			Component:     message.Metric,
+			Group:         message.Check,
+			Class:         message.Title,
+			CustomDetails: details,
+		},
+	}
+}
+
+// pagerDutySeverity maps a notification level to a PagerDuty severity
+func pagerDutySeverity(level NotificationLevel) string {
+	switch level {
+	case NotificationLevelError:
+		return "critical"
+	case NotificationLevelWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// recordedRequest is a request received by a requestRecorder
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   []byte
+}
+
+// requestRecorder is a stand-in API server that records every request
+type requestRecorder struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+func newRequestRecorder(t *testing.T) *requestRecorder {
+	t.Helper()
+	recorder := &requestRecorder{}
+	recorder.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		recorder.requests = append(recorder.requests, recordedRequest{
+			Method: r.Method,
+			Path:   r.URL.EscapedPath(),
+			Query:  r.URL.RawQuery,
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(recorder.Close)
+	return recorder
+}
+
+// received returns the requests recorded so far
+func (rr *requestRecorder) received() []recordedRequest {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	return append([]recordedRequest(nil), rr.requests...)
+}
+
+func TestPagerDutyTriggerAndResolve(t *testing.T) {
+	server := newRequestRecorder(t)
+	provider := NewPagerDutyProvider("routing-key", server.URL+"/v2/enqueue", server.Client())
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	alert := testMessage()
+	info := testMessage()
+	info.Level = NotificationLevelInfo
+	if err := provider.Send(t.Context(), alert); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if err := provider.Send(t.Context(), info); err != nil {
+		t.Fatalf("Send(info) error = %v", err)
+	}
+	if err := provider.Resolve(t.Context(), alert); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	requests := server.received()
+	if len(requests) != 2 {
+		t.Fatalf("received %d requests, want trigger and resolve", len(requests))
+	}
+
+	wantKey := "serverhealth/web-1/disk"
+	for i, want := range []struct {
+		action     string
+		hasPayload bool
+	}{{"trigger", true}, {"resolve", false}} {
+		request := requests[i]
+		if request.Method != http.MethodPost || request.Path != "/v2/enqueue" {
+			t.Errorf("%s sent as %s %s", want.action, request.Method, request.Path)
+		}
+
+		var event pagerDutyEvent
+		if err := json.Unmarshal(request.Body, &event); err != nil {
+			t.Fatalf("%s body %s: %v", want.action, request.Body, err)
+		}
+		if event.EventAction != want.action {
+			t.Errorf("event_action = %s, want %s", event.EventAction, want.action)
+		}
+		if event.RoutingKey != "routing-key" {
+			t.Errorf("%s routing_key = %q", want.action, event.RoutingKey)
+		}
+		if event.DedupKey != wantKey {
+			t.Errorf("%s dedup_key = %q, want %q", want.action, event.DedupKey, wantKey)
+		}
+		if (event.Payload != nil) != want.hasPayload {
+			t.Errorf("%s payload = %+v", want.action, event.Payload)
+		}
+	}
+
+	var trigger pagerDutyEvent
+	json.Unmarshal(requests[0].Body, &trigger)
+	if trigger.Payload.Severity != "critical" || trigger.Payload.Source != "web-1" || trigger.Payload.Group != "disk" {
+		t.Errorf("trigger payload = %+v", trigger.Payload)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// defaultPushoverURL is the Pushover message API endpoint
+	defaultPushoverURL = "https://api.pushover.net/1/messages.json"
+
+	// Emergency priority retry and expiry limits of the Pushover API, in seconds
+	defaultPushoverRetry  = 60
+	defaultPushoverExpire = 3600
+	minPushoverRetry      = 30
+	maxPushoverExpire     = 10800
+
+	// Field limits of the Pushover API
+	pushoverMaxTitle   = 250
+	pushoverMaxMessage = 1024
+)
+
+// PushoverProvider implements NotificationProvider for Pushover
+type PushoverProvider struct {
+	AppToken string
+	UserKey  string
+	Retry    int
+	Expire   int
+	URL      string
+	Template TemplateConfig
+	template *MessageTemplate
+	client   *http.Client
+}
+
+// NewPushoverProvider creates a new Pushover notification provider. Error alerts
+// use emergency priority, repeating every retry seconds until acknowledged or
+// expire seconds pass. An empty endpoint URL uses the Pushover API.
+func NewPushoverProvider(appToken, userKey string, retry, expire int, endpoint string, tmpl TemplateConfig, client *http.Client) *PushoverProvider {
+	if retry == 0 {
+		retry = defaultPushoverRetry
+	}
+	if expire == 0 {
+		expire = defaultPushoverExpire
+	}
+	if endpoint == "" {
+		endpoint = defaultPushoverURL
+	}
+
+	return &PushoverProvider{
+		AppToken: appToken,
+		UserKey:  userKey,
+		Retry:    retry,
+		Expire:   expire,
+		URL:      endpoint,
+		Template: tmpl,
+		client:   client,
+	}
+}
+
+// Validate validates the Pushover provider configuration
+func (pp *PushoverProvider) Validate() error {
+	if pp.AppToken == "" {
+		return fmt.Errorf("application token is required")
+	}
+
+	if pp.UserKey == "" {
+		return fmt.Errorf("user key is required")
+	}
+
+	if pp.Retry < minPushoverRetry {
+		return fmt.Errorf("retry must be at least %d seconds", minPushoverRetry)
+	}
+
+	if pp.Expire < pp.Retry || pp.Expire > maxPushoverExpire {
+		return fmt.Errorf("expire must be between the retry interval and %d seconds", maxPushoverExpire)
+	}
+
+	parsed, err := url.Parse(pp.URL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("Pushover URL must be an http:// or https:// URL")
+	}
+
+	tmpl, err := pp.Template.Load()
+	if err != nil {
+		return err
+	}
+	pp.template = tmpl
+
+	return nil
+}
+
+// GetType returns the notification type
+func (pp *PushoverProvider) GetType() NotificationType {
+	return NotificationTypePushover
+}
+
+// Send sends a notification to Pushover
+func (pp *PushoverProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	priority := pushoverPriority(message.Level)
+	title, text := pp.template.Render(message, hostTitle, pushText)
+
+	form := url.Values{
+		"token":     {pp.AppToken},
+		"user":      {pp.UserKey},
+		"title":     {truncate(title, pushoverMaxTitle)},
+		"message":   {truncate(text, pushoverMaxMessage)},
+		"priority":  {strconv.Itoa(priority)},
+		"timestamp": {strconv.FormatInt(message.Timestamp.Unix(), 10)},
+	}
+
+	// Emergency notifications repeat until acknowledged in the Pushover app
+	if priority == 2 {
+		form.Set("retry", strconv.Itoa(pp.Retry))
+		form.Set("expire", strconv.Itoa(pp.Expire))
+	}
+
+	return sendHTTP(ctx, pp.client, httpRequest{
+		Method:  http.MethodPost,
+		URL:     pp.URL,
+		Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		Body:    []byte(form.Encode()),
+	})
+}
+
+// pushoverPriority maps a notification level to a Pushover priority: emergency
+// for errors, high for warnings and normal otherwise
+func pushoverPriority(level NotificationLevel) int {
+	switch level {
+	case NotificationLevelError:
+		return 2
+	case NotificationLevelWarning:
+		return 1
+	default:
+		return 0
+	}
+}
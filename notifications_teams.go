@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TeamsProvider implements NotificationProvider for Microsoft Teams incoming
+// webhooks and Workflows (Power Automate) webhook URLs
+type TeamsProvider struct {
+	WebhookURL string
+	Template   TemplateConfig
+	template   *MessageTemplate
+	client     *http.Client
+}
+
+// NewTeamsProvider creates a new Microsoft Teams notification provider.
+// Templates replace the card heading and text.
+func NewTeamsProvider(webhookURL string, tmpl TemplateConfig, client *http.Client) *TeamsProvider {
+	return &TeamsProvider{
+		WebhookURL: webhookURL,
+		Template:   tmpl,
+		client:     client,
+	}
+}
+
+// Validate validates the Teams provider configuration
+func (tp *TeamsProvider) Validate() error {
+	if tp.WebhookURL == "" {
+		return fmt.Errorf("webhook URL is required")
+	}
+
+	parsed, err := url.Parse(tp.WebhookURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return fmt.Errorf("webhook URL must use HTTPS")
+	}
+
+	tmpl, err := tp.Template.Load()
+	if err != nil {
+		return err
+	}
+	tp.template = tmpl
+
+	return nil
+}
+
+// GetType returns the notification type
+func (tp *TeamsProvider) GetType() NotificationType {
+	return NotificationTypeTeams
+}
+
+// Send sends a notification to Teams as an Adaptive Card
+func (tp *TeamsProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	style, color := teamsLevelStyle(message.Level)
+	title, text := tp.template.Render(message, emojiTitle, alertText)
+
+	body := []map[string]interface{}{
+		{
+			"type":  "Container",
+			"style": style,
+			"bleed": true,
+			"items": []map[string]interface{}{
+				{
+					"type":   "TextBlock",
+					"text":   title,
+					"size":   "Medium",
+					"weight": "Bolder",
+					"color":  color,
+					"wrap":   true,
+				},
+			},
+		},
+		{
+			"type": "TextBlock",
+			"text": text,
+			"wrap": true,
+		},
+	}
+
+	if len(message.Group) > 0 {
+		// Grouped alerts get a heading and facts each
+		body = append(body, teamsFactSet([][2]string{
+			{"Server", fmt.Sprintf("%s (%s)", message.Hostname, message.IP)},
+		}))
+		for _, alert := range message.Group {
+			_, alertColor := teamsLevelStyle(alert.Level)
+			body = append(body,
+				map[string]interface{}{
+					"type":      "TextBlock",
+					"text":      fmt.Sprintf("%s %s", levelEmoji(alert.Level), alert.Title),
+					"weight":    "Bolder",
+					"color":     alertColor,
+					"separator": true,
+					"wrap":      true,
+				},
+				teamsFactSet(alertFacts(alert, false)),
+			)
+		}
+	} else {
+		body = append(body, teamsFactSet(alertFacts(message, true)))
+	}
+
+	body = append(body, map[string]interface{}{
+		"type":     "TextBlock",
+		"text":     message.Timestamp.Format(time.RFC1123),
+		"size":     "Small",
+		"isSubtle": true,
+		"wrap":     true,
+	})
+
+	payload := map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{
+			{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]interface{}{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    body,
+					"msteams": map[string]interface{}{"width": "Full"},
+				},
+			},
+		},
+	}
+
+	return sendHTTPRequest(ctx, tp.client, tp.WebhookURL, payload)
+}
+
+// teamsLevelStyle returns the Adaptive Card container style and text colour for
+// a notification level; Adaptive Cards only support named colours
+func teamsLevelStyle(level NotificationLevel) (style, color string) {
+	switch level {
+	case NotificationLevelWarning:
+		return "warning", "Warning"
+	case NotificationLevelError:
+		return "attention", "Attention"
+	default:
+		return "accent", "Accent"
+	}
+}
+
+// teamsFactSet builds an Adaptive Card FactSet from name/value pairs
+func teamsFactSet(facts [][2]string) map[string]interface{} {
+	items := make([]map[string]string, 0, len(facts))
+	for _, fact := range facts {
+		items = append(items, map[string]string{"title": fact[0], "value": fact[1]})
+	}
+	return map[string]interface{}{
+		"type":  "FactSet",
+		"facts": items,
+	}
+}
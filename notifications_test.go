@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// redirectTransport sends every request to a test server, whatever its URL
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = rt.target.Scheme
+	req.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// checkTelegramMarkdown mimics Telegram's legacy Markdown parser closely enough
+// to reject the unbalanced entities it answers with "can't parse entities"
+func checkTelegramMarkdown(text string) error {
+	var open rune
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case open == 0 && c == '\\' && i+1 < len(runes) && strings.ContainsRune("_*`[", runes[i+1]):
+			i++
+		case open != 0:
+			if c == open {
+				open = 0
+			}
+		case c == '_' || c == '*' || c == '`':
+			open = c
+		case c == '[':
+			end := strings.IndexRune(string(runes[i:]), ']')
+			if end < 0 {
+				return fmt.Errorf("can't find end of the entity starting at %d", i)
+			}
+		}
+	}
+	if open != 0 {
+		return fmt.Errorf("can't find end of %q entity", open)
+	}
+	return nil
+}
+
+func TestTelegramEscapesMarkdown(t *testing.T) {
+	if checkTelegramMarkdown("memory.used_percent > 90") == nil {
+		t.Fatal("checker accepted an unbalanced underscore")
+	}
+
+	var texts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Text      string `json:"text"`
+			ParseMode string `json:"parse_mode"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ParseMode != "Markdown" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if err := checkTelegramMarkdown(payload.Text); err != nil {
+			http.Error(w, "Bad Request: can't parse entities: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		texts = append(texts, payload.Text)
+	}))
+	defer server.Close()
+
+	target, _ := url.Parse(server.URL)
+	client := &http.Client{Transport: redirectTransport{target: target}, Timeout: 5 * time.Second}
+	provider := NewTelegramProvider("token", "42", TemplateConfig{}, client)
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A rule alert carries the raw expression and metric names
+	rule := &NotificationMessage{
+		Level:     NotificationLevelWarning,
+		Title:     "Rule Alert: swap_*pressure*",
+		Message:   "Rule swap_pressure matched: memory.used_percent > 90 && swap.used_percent > 50",
+		Hostname:  "web_1",
+		IP:        "10.0.0.1",
+		Metric:    "swap_pressure",
+		Value:     "memory.used_percent=93.00, swap.used_percent=61.00",
+		Threshold: "memory.used_percent > 90 && swap.used_percent > 50",
+		AlertID:   "a1",
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	grouped := &NotificationMessage{
+		Level:     NotificationLevelWarning,
+		Title:     "2 alerts",
+		Hostname:  "web_1",
+		Timestamp: rule.Timestamp,
+		Group:     []*NotificationMessage{rule, testMessage()},
+	}
+
+	for _, message := range []*NotificationMessage{rule, grouped} {
+		if err := provider.Send(t.Context(), message); err != nil {
+			t.Fatalf("Send(%s) error = %v", message.Title, err)
+		}
+	}
+
+	if len(texts) != 2 {
+		t.Fatalf("received %d messages, want 2", len(texts))
+	}
+	for _, want := range []string{`*Rule Alert: swap_*\**pressure*\***`, `Rule swap\_pressure matched: memory.used\_percent > 90`, "*Alert ID:* `a1`"} {
+		if !strings.Contains(texts[0], want) {
+			t.Errorf("message %q does not contain %q", texts[0], want)
+		}
+	}
+	if !strings.Contains(texts[1], `memory.used\_percent=93.00`) {
+		t.Errorf("grouped message %q does not escape alert values", texts[1])
+	}
+}
+
+func TestSlackMarkdownUnescaped(t *testing.T) {
+	message := testMessage()
+	message.Metric = "memory.used_percent"
+	if body := slackMarkdown.body(message); !strings.Contains(body, "*Metric:* memory.used_percent") {
+		t.Errorf("Slack body = %q", body)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+)
+
+// WebhookProvider implements NotificationProvider for arbitrary HTTP endpoints
+type WebhookProvider struct {
+	URL      string
+	Method   string
+	Headers  map[string]string
+	Payload  string
+	template *template.Template
+	client   *http.Client
+}
+
+// NewWebhookProvider creates a new webhook notification provider. The payload is
+// a text/template rendered from the NotificationMessage; when empty, the message
+// is sent as JSON.
+func NewWebhookProvider(webhookURL, method string, headers map[string]string, payload string, client *http.Client) *WebhookProvider {
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	return &WebhookProvider{
+		URL:     webhookURL,
+		Method:  strings.ToUpper(method),
+		Headers: headers,
+		Payload: payload,
+		client:  client,
+	}
+}
+
+// Validate validates the webhook provider configuration and parses its payload template
+func (wp *WebhookProvider) Validate() error {
+	if wp.URL == "" {
+		return fmt.Errorf("webhook URL is required")
+	}
+
+	parsed, err := url.Parse(wp.URL)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("webhook URL must be an http:// or https:// URL")
+	}
+
+	switch wp.Method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return fmt.Errorf("method must be one of: GET, POST, PUT, PATCH")
+	}
+
+	if wp.Payload != "" {
+		tmpl, err := template.New("payload").Funcs(templateFuncs).Option("missingkey=zero").Parse(wp.Payload)
+		if err != nil {
+			return fmt.Errorf("invalid payload template: %w", err)
+		}
+		wp.template = tmpl
+	}
+
+	return nil
+}
+
+// GetType returns the notification type
+func (wp *WebhookProvider) GetType() NotificationType {
+	return NotificationTypeWebhook
+}
+
+// Send sends a notification to the webhook URL
+func (wp *WebhookProvider) Send(ctx context.Context, message *NotificationMessage) error {
+	// GET requests carry no payload
+	var body []byte
+	if wp.Method != http.MethodGet {
+		var err error
+		if body, err = wp.render(message); err != nil {
+			return err
+		}
+	}
+
+	headers := map[string]string{"Content-Type": "application/json"}
+	for name, value := range wp.Headers {
+		headers[name] = value
+	}
+
+	return sendHTTP(ctx, wp.client, httpRequest{
+		Method:  wp.Method,
+		URL:     wp.URL,
+		Headers: headers,
+		Body:    body,
+	})
+}
+
+// render returns the request body for a message. The template is parsed by Validate.
+func (wp *WebhookProvider) render(message *NotificationMessage) ([]byte, error) {
+	if wp.template == nil {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	if err := wp.template.Execute(&buf, message); err != nil {
+		return nil, fmt.Errorf("failed to render payload template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
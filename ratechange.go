@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// changeCheckKey returns the check name used for rate-of-change alerts of a metric
+func changeCheckKey(metricKey string) string {
+	return metricKey + "-change"
+}
+
+// String returns a short description such as "+30 per 5m"
+func (ct ChangeThreshold) String() string {
+	return fmt.Sprintf("%+g per %s", ct.Delta, formatMinutes(ct.WindowMinutes))
+}
+
+// Exceeded reports whether a change matches the threshold's direction and size
+func (ct ChangeThreshold) Exceeded(change float64) bool {
+	if ct.Delta < 0 {
+		return change <= ct.Delta
+	}
+	return change >= ct.Delta
+}
+
+// checkChange compares recent samples of a metric against its rate-of-change
+// thresholds and alerts on the first one exceeded. The caller must hold m.mu.
+func (m *Monitor) checkChange(metricKey string, config MonitoringConfig, metricName string) {
+	if len(config.ChangeThresholds) == 0 {
+		return
+	}
+
+	now := time.Now()
+	// Widen each window by half a check interval so the sample taken one
+	// window ago is still included despite timer drift
+	slack := m.checkInterval(metricKey) / 2
+
+	var exceeded *ChangeThreshold
+	var change float64
+	var elapsed time.Duration
+	for i, threshold := range config.ChangeThresholds {
+		window := time.Duration(threshold.WindowMinutes) * time.Minute
+		delta, span, ok := m.history.Change(metricSeries[metricKey], now.Add(-window-slack))
+		if ok && threshold.Exceeded(delta) {
+			exceeded, change, elapsed = &config.ChangeThresholds[i], delta, span
+			break
+		}
+	}
+
+	checkKey := changeCheckKey(metricKey)
+	state := m.updateCheckState(checkKey, exceeded != nil)
+	if exceeded == nil || state.Flapping {
+		return
+	}
+
+	if recentlySent(state, m.checkInterval(metricKey)) {
+		return
+	}
+
+	level := NotificationLevelWarning
+	if exceeded.Level != "" {
+		level = NotificationLevel(exceeded.Level)
+	}
+
+	direction := "rose"
+	if change < 0 {
+		direction = "fell"
+	}
+
+	hostname, serverIP := GetServerInfo()
+	message := &NotificationMessage{
+		Type:  NotificationTypeSlack, // Will be overridden by providers
+		Level: level,
+		Title: fmt.Sprintf("%s Usage Change Alert", metricName),
+		Message: fmt.Sprintf("%s usage %s by %.2f points in the last %s",
+			metricName, direction, math.Abs(change), formatMinutes(int(math.Round(elapsed.Minutes())))),
+		Hostname:  hostname,
+		IP:        serverIP,
+		Timestamp: now,
+		Metric:    fmt.Sprintf("%s Usage", metricName),
+		Value:     fmt.Sprintf("%+.2f%%", change),
+		Threshold: exceeded.String(),
+		AlertID:   state.AlertID,
+		Since:     state.Since,
+		Check:     checkKey,
+		Labels:    m.alertLabels(metricKey),
+	}
+
+	m.notify(message, config.EffectiveRateLimits(), nil)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SlidingWindowLimiter enforces a set of "max N per window" limits over the
+// exact times alerts were sent, so limits don't reset at calendar boundaries
+type SlidingWindowLimiter struct {
+	Sent []time.Time `json:"sent,omitempty"`
+	// Notified is set once the "further alerts suppressed" notice has been sent
+	// for the current limited period
+	Notified bool `json:"notified,omitempty"`
+}
+
+// prune drops send times that fall outside every limit window
+func (l *SlidingWindowLimiter) prune(limits []RateLimit, now time.Time) {
+	var longest time.Duration
+	for _, limit := range limits {
+		if limit.Window() > longest {
+			longest = limit.Window()
+		}
+	}
+
+	i := 0
+	for i < len(l.Sent) && now.Sub(l.Sent[i]) >= longest {
+		i++
+	}
+	l.Sent = l.Sent[i:]
+}
+
+// Exceeded returns the first limit that has been reached, or nil if another
+// alert may be sent now
+func (l *SlidingWindowLimiter) Exceeded(limits []RateLimit, now time.Time) *RateLimit {
+	l.prune(limits, now)
+
+	for i, limit := range limits {
+		count := 0
+		for _, sent := range l.Sent {
+			if now.Sub(sent) < limit.Window() {
+				count++
+			}
+		}
+		if count >= limit.Max {
+			return &limits[i]
+		}
+	}
+	return nil
+}
+
+// Record notes that an alert was sent
+func (l *SlidingWindowLimiter) Record(now time.Time) {
+	l.Sent = append(l.Sent, now)
+	l.Notified = false
+}
+
+// String returns a human readable form of the limit such as "3 per 1h"
+func (r RateLimit) String() string {
+	return fmt.Sprintf("%d per %s", r.Max, formatMinutes(r.WindowMinutes))
+}
+
+// formatMinutes returns a compact duration such as "5m", "2h" or "1d"
+func formatMinutes(minutes int) string {
+	switch {
+	case minutes > 0 && minutes%(24*60) == 0:
+		return fmt.Sprintf("%dd", minutes/(24*60))
+	case minutes > 0 && minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+// Window returns the length of the limit window
+func (r RateLimit) Window() time.Duration {
+	return time.Duration(r.WindowMinutes) * time.Minute
+}
+
+// describeRateLimits returns a comma separated summary of the limits
+func describeRateLimits(limits []RateLimit) string {
+	if len(limits) == 0 {
+		return "unlimited"
+	}
+	parts := make([]string, len(limits))
+	for i, limit := range limits {
+		parts[i] = limit.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// allowProvider applies a provider's rate limits to an alert, sending a single
+// "further alerts suppressed" notice when the limit is first hit. The caller
+// must hold nm.mu.
+func (nm *NotificationManager) allowProvider(provider namedProvider, message *NotificationMessage) bool {
+	limiter, ok := nm.limiters[provider.name]
+	if !ok {
+		limiter = &SlidingWindowLimiter{}
+		nm.limiters[provider.name] = limiter
+	}
+
+	now := time.Now()
+	limit := limiter.Exceeded(provider.rateLimits, now)
+	if limit == nil {
+		limiter.Record(now)
+		return true
+	}
+
+	if !limiter.Notified {
+		limiter.Notified = true
+		nm.logger.Printf("Rate limit of %s reached for provider %s, suppressing further alerts", limit, provider.name)
+
+		notice := &NotificationMessage{
+			Type:  message.Type,
+			Level: NotificationLevelWarning,
+			Title: fmt.Sprintf("Further alerts to %s suppressed", provider.name),
+			Message: fmt.Sprintf("The alert rate limit for this channel (%s) has been reached. Further alerts are suppressed until the limit frees up.",
+				limit),
+			Hostname:  message.Hostname,
+			IP:        message.IP,
+			Timestamp: now,
+			Metric:    message.Metric,
+			Value:     message.Value,
+			Threshold: message.Threshold,
+			Labels:    message.Labels,
+		}
+
+		nm.inFlight.Add(1)
+		go func() {
+			defer nm.inFlight.Done()
+			if err := provider.provider.Send(context.Background(), notice); err != nil {
+				nm.logger.Printf("Failed to send rate limit notice via %s: %v", provider.name, err)
+			}
+		}()
+	}
+	return false
+}
+
+// SnapshotLimiters returns a copy of the per-provider rate limiter state for persistence
+func (nm *NotificationManager) SnapshotLimiters() map[string]*SlidingWindowLimiter {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	snapshot := make(map[string]*SlidingWindowLimiter, len(nm.limiters))
+	for name, limiter := range nm.limiters {
+		copied := *limiter
+		copied.Sent = append([]time.Time(nil), limiter.Sent...)
+		snapshot[name] = &copied
+	}
+	return snapshot
+}
+
+// RestoreLimiters replaces the per-provider rate limiter state with a saved copy
+func (nm *NotificationManager) RestoreLimiters(limiters map[string]*SlidingWindowLimiter) {
+	if limiters == nil {
+		return
+	}
+
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	nm.limiters = limiters
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlidingWindowLimiterExceeded(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	limits := []RateLimit{{Max: 2, WindowMinutes: 10}, {Max: 3, WindowMinutes: 60}}
+
+	tests := []struct {
+		name string
+		// sent holds how many minutes before now each alert was sent, oldest first
+		sent      []int
+		want      int // index of the exceeded limit, or -1
+		wantCount int // send times kept after pruning
+	}{
+		{name: "nothing sent", want: -1},
+		{name: "below both limits", sent: []int{30, 5}, want: -1, wantCount: 2},
+		{name: "short window full", sent: []int{9, 1}, want: 0, wantCount: 2},
+		{name: "long window full", sent: []int{50, 30, 5}, want: 1, wantCount: 3},
+		{name: "short window slides", sent: []int{10, 1}, want: -1, wantCount: 2},
+		{name: "old sends pruned", sent: []int{120, 60, 20, 5}, want: -1, wantCount: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := &SlidingWindowLimiter{}
+			for _, minutes := range tt.sent {
+				limiter.Record(now.Add(-time.Duration(minutes) * time.Minute))
+			}
+
+			got := limiter.Exceeded(limits, now)
+			switch {
+			case tt.want < 0 && got != nil:
+				t.Errorf("Exceeded() = %v, want nil", got)
+			case tt.want >= 0 && got != &limits[tt.want]:
+				t.Errorf("Exceeded() = %v, want %v", got, limits[tt.want])
+			}
+			if len(limiter.Sent) != tt.wantCount {
+				t.Errorf("kept %d send times, want %d", len(limiter.Sent), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestSlidingWindowLimiterRecordResetsNotified(t *testing.T) {
+	limiter := &SlidingWindowLimiter{Notified: true}
+	limiter.Record(time.Now())
+	if limiter.Notified {
+		t.Error("Record() kept Notified set")
+	}
+}
+
+func TestDescribeRateLimits(t *testing.T) {
+	tests := []struct {
+		limits []RateLimit
+		want   string
+	}{
+		{nil, "unlimited"},
+		{[]RateLimit{{Max: 5, WindowMinutes: 30}}, "5 per 30m"},
+		{[]RateLimit{{Max: 3, WindowMinutes: 60}, {Max: 10, WindowMinutes: 24 * 60}}, "3 per 1h, 10 per 1d"},
+		{[]RateLimit{{Max: 1, WindowMinutes: 90}}, "1 per 90m"},
+		{[]RateLimit{{Max: 2, WindowMinutes: 48 * 60}}, "2 per 2d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := describeRateLimits(tt.limits); got != tt.want {
+				t.Errorf("describeRateLimits() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
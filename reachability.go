@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// monitorReachability dials a reachability check's endpoint on every interval
+func (m *Monitor) monitorReachability(check ReachabilityConfig) {
+	ticker := time.NewTicker(check.EffectiveInterval())
+	defer ticker.Stop()
+
+	m.checkReachability(check)
+	for {
+		select {
+		case <-ticker.C:
+			m.checkReachability(check)
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// checkReachability opens a TCP connection to the endpoint and notifies while it fails
+func (m *Monitor) checkReachability(check ReachabilityConfig) {
+	dialer := net.Dialer{Timeout: check.EffectiveTimeout()}
+	conn, err := dialer.DialContext(m.ctx, "tcp", check.Address)
+	if err == nil {
+		conn.Close()
+	}
+	if m.ctx.Err() != nil {
+		return // Shutting down, not unreachable
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	firing := err != nil
+	state := m.updateCheckState(check.Name, firing)
+	defer m.saveStateIfChanged()
+	if !firing || state.Flapping {
+		return
+	}
+
+	if recentlySent(state, check.EffectiveInterval()) {
+		return
+	}
+
+	level := NotificationLevel(check.Level)
+	if check.Level == "" {
+		level = NotificationLevelError
+	}
+
+	labels := m.alertLabels(check.Name)
+	for key, value := range check.Labels {
+		labels[key] = value
+	}
+
+	hostname, serverIP := GetServerInfo()
+	message := &NotificationMessage{
+		Type:      NotificationTypeSlack, // Will be overridden by providers
+		Level:     level,
+		Title:     fmt.Sprintf("Unreachable: %s", check.Name),
+		Message:   fmt.Sprintf("Cannot connect to %s: %v", check.Address, err),
+		Hostname:  hostname,
+		IP:        serverIP,
+		Timestamp: time.Now(),
+		Metric:    check.Name,
+		Value:     "unreachable",
+		Threshold: check.Address,
+		AlertID:   state.AlertID,
+		Since:     state.Since,
+		Check:     check.Name,
+		Labels:    labels,
+	}
+
+	m.notify(message, check.EffectiveRateLimits(), nil)
+}
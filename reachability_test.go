@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestReachabilitySuppressesDependents(t *testing.T) {
+	// A listener that was closed leaves a port that refuses connections
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed.Close()
+	open, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer open.Close()
+
+	gateway := ReachabilityConfig{Name: "gateway", Address: closed.Addr().String()}
+	service := ReachabilityConfig{Name: "service", Address: closed.Addr().String(), DependsOn: []string{"gateway"}}
+	database := ReachabilityConfig{Name: "database", Address: open.Addr().String(), DependsOn: []string{"gateway"}}
+
+	provider := &recordingProvider{}
+	m := newTestMonitor(t, provider)
+	m.config = &Config{Reachability: []ReachabilityConfig{gateway, service, database}}
+	m.dependencies = m.config.checkDependencies()
+
+	for _, check := range m.config.Reachability {
+		m.checkReachability(check)
+	}
+
+	if len(provider.sent) != 1 || provider.sent[0].Check != "gateway" {
+		t.Fatalf("sent %d alerts, want only the gateway alert", len(provider.sent))
+	}
+	if !strings.Contains(provider.sent[0].Message, gateway.Address) {
+		t.Errorf("message = %q, want the address", provider.sent[0].Message)
+	}
+	for name, want := range map[string]checkState{
+		"gateway":  {Firing: true},
+		"service":  {Firing: true, Unknown: true},
+		"database": {Firing: false, Unknown: true},
+	} {
+		state := m.checkStates[name]
+		if state.Firing != want.Firing || state.Unknown != want.Unknown {
+			t.Errorf("%s: firing = %v, unknown = %v, want %v, %v", name, state.Firing, state.Unknown, want.Firing, want.Unknown)
+		}
+	}
+}
+
+func TestValidateReachability(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   ReachabilityConfig
+		wantErr string
+	}{
+		{name: "valid", check: ReachabilityConfig{Name: "gateway", Address: "192.168.1.1:53", Interval: "30s", Timeout: "3s"}},
+		{name: "defaults", check: ReachabilityConfig{Name: "gateway", Address: "[fe80::1]:53"}},
+		{name: "reserved name", check: ReachabilityConfig{Name: "cpu-anomaly", Address: "192.168.1.1:53"}, wantErr: "reserved"},
+		{name: "no port", check: ReachabilityConfig{Name: "gateway", Address: "192.168.1.1"}, wantErr: "must be host:port"},
+		{name: "timeout too long", check: ReachabilityConfig{Name: "gateway", Address: "192.168.1.1:53", Interval: "30s", Timeout: "30s"},
+			wantErr: "timeout must be shorter"},
+		{name: "bad level", check: ReachabilityConfig{Name: "gateway", Address: "192.168.1.1:53", Level: "fatal"}, wantErr: "invalid level"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateReachability(&tt.check)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateReachability() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateReachability() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
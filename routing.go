@@ -0,0 +1,95 @@
+package main
+
+import (
+	"strings"
+)
+
+// Router selects notification providers for a message based on routing rules
+type Router struct {
+	routes []RouteConfig
+}
+
+// NewRouter creates a new router from the configured routes
+func NewRouter(routes []RouteConfig) *Router {
+	return &Router{routes: routes}
+}
+
+// Route returns the provider names the message should be sent to.
+// Routes are evaluated in order and the first match wins unless it sets
+// continue, in which case later routes are evaluated as well. An empty
+// result means no route matched.
+func (r *Router) Route(message *NotificationMessage) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, route := range r.routes {
+		if !route.Match.Matches(message) {
+			continue
+		}
+
+		for _, name := range route.Providers {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+
+		if !route.Continue {
+			break
+		}
+	}
+
+	return names
+}
+
+// Matches reports whether the message satisfies every condition of the match.
+// Empty conditions match everything.
+func (rm RouteMatch) Matches(message *NotificationMessage) bool {
+	if len(rm.Metrics) > 0 && !containsFold(rm.Metrics, message.Labels["metric"]) {
+		return false
+	}
+
+	if len(rm.Levels) > 0 && !containsFold(rm.Levels, string(message.Level)) {
+		return false
+	}
+
+	if len(rm.Checks) > 0 && !containsFold(rm.Checks, message.Check) {
+		return false
+	}
+
+	for key, value := range rm.Labels {
+		if message.Labels[key] != value {
+			return false
+		}
+	}
+
+	if len(rm.During) > 0 {
+		active := false
+		for _, window := range rm.During {
+			if window.Contains(message.Timestamp) {
+				active = true
+				break
+			}
+		}
+		if !active {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isEmpty reports whether the match has no conditions and therefore matches everything
+func (rm RouteMatch) isEmpty() bool {
+	return len(rm.Metrics) == 0 && len(rm.Levels) == 0 && len(rm.Checks) == 0 && len(rm.Labels) == 0 && len(rm.During) == 0
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
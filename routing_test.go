@@ -0,0 +1,90 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRouteMatchMatches(t *testing.T) {
+	// Wednesday 10:30 UTC
+	now := time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)
+	message := &NotificationMessage{
+		Level:     NotificationLevelError,
+		Check:     "disk",
+		Labels:    map[string]string{"metric": "disk", "env": "prod"},
+		Timestamp: now,
+	}
+
+	tests := []struct {
+		name  string
+		match RouteMatch
+		want  bool
+	}{
+		{"empty matches everything", RouteMatch{}, true},
+		{"metric", RouteMatch{Metrics: []string{"cpu", "disk"}}, true},
+		{"other metric", RouteMatch{Metrics: []string{"cpu"}}, false},
+		{"level ignores case", RouteMatch{Levels: []string{"ERROR"}}, true},
+		{"other level", RouteMatch{Levels: []string{"warning"}}, false},
+		{"check", RouteMatch{Checks: []string{"disk"}}, true},
+		{"other check", RouteMatch{Checks: []string{"cpu"}}, false},
+		{"label", RouteMatch{Labels: map[string]string{"env": "prod"}}, true},
+		{"other label value", RouteMatch{Labels: map[string]string{"env": "staging"}}, false},
+		{"missing label", RouteMatch{Labels: map[string]string{"team": "ops"}}, false},
+		{"all conditions", RouteMatch{Metrics: []string{"disk"}, Levels: []string{"error"}, Labels: map[string]string{"env": "prod"}}, true},
+		{"one condition fails", RouteMatch{Metrics: []string{"disk"}, Levels: []string{"info"}}, false},
+		{"during window", RouteMatch{During: []TimeWindow{{Start: "09:00", End: "17:00"}}}, true},
+		{"outside window", RouteMatch{During: []TimeWindow{{Start: "18:00", End: "08:00"}}}, false},
+		{"any window", RouteMatch{During: []TimeWindow{{Days: []string{"saturday"}}, {Days: []string{"Wednesday"}}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.match.Matches(message); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterRoute(t *testing.T) {
+	routes := []RouteConfig{
+		{Match: RouteMatch{Levels: []string{"error"}}, Providers: []string{"pagerduty", "slack"}, Continue: true},
+		{Match: RouteMatch{Metrics: []string{"disk"}}, Providers: []string{"slack", "email"}},
+		{Match: RouteMatch{Metrics: []string{"cpu"}}, Providers: []string{"telegram"}},
+		{Match: RouteMatch{}, Providers: []string{"fallback"}},
+	}
+	router := NewRouter(routes)
+
+	tests := []struct {
+		name    string
+		message *NotificationMessage
+		want    []string
+	}{
+		{
+			name:    "continue collects later routes without duplicates",
+			message: &NotificationMessage{Level: NotificationLevelError, Labels: map[string]string{"metric": "disk"}},
+			want:    []string{"pagerduty", "slack", "email"},
+		},
+		{
+			name:    "first match wins",
+			message: &NotificationMessage{Level: NotificationLevelWarning, Labels: map[string]string{"metric": "cpu"}},
+			want:    []string{"telegram"},
+		},
+		{
+			name:    "catch-all route",
+			message: &NotificationMessage{Level: NotificationLevelInfo},
+			want:    []string{"fallback"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := router.Route(tt.message); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Route() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := NewRouter(nil).Route(&NotificationMessage{}); len(got) != 0 {
+		t.Errorf("Route() without routes = %v, want none", got)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ruleMetricSources maps the metrics available to rule expressions to their collectors
+var ruleMetricSources = map[string]func() (float64, error){
+	"disk.used_percent":   getDiskUsageFloat64,
+	"cpu.usage":           GetCPUUsage,
+	"memory.used_percent": GetMemoryUsage,
+	"swap.used_percent":   GetSwapUsage,
+}
+
+// ruleMetricNames returns the sorted names of the metrics available to rules
+func ruleMetricNames() []string {
+	names := make([]string, 0, len(ruleMetricSources))
+	for name := range ruleMetricSources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// compiledRule is a custom alert rule with its parsed expression
+type compiledRule struct {
+	config  RuleConfig
+	expr    Expr
+	metrics []string
+}
+
+// compileRules parses the expressions of all configured rules
+func compileRules(rules []RuleConfig) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		expr, err := ParseExpr(rule.Expr)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		compiled = append(compiled, compiledRule{config: rule, expr: expr, metrics: exprMetrics(expr)})
+	}
+	return compiled, nil
+}
+
+// runRules evaluates the custom alert rules on every rule interval
+func (m *Monitor) runRules() {
+	rules, err := compileRules(m.config.Rules)
+	if err != nil {
+		m.logger.Printf("Custom rules disabled: %v", err)
+		return
+	}
+
+	interval := parseInterval(m.config.RuleInterval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m.evaluateRules(rules, interval)
+	for {
+		select {
+		case <-ticker.C:
+			m.evaluateRules(rules, interval)
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// evaluateRules samples the metrics used by the rules and evaluates each rule
+func (m *Monitor) evaluateRules(rules []compiledRule, interval time.Duration) {
+	needed := make(map[string]bool)
+	for _, rule := range rules {
+		for _, metric := range rule.metrics {
+			needed[metric] = true
+		}
+	}
+	for metric := range needed {
+		m.collectRuleSample(metric, interval)
+	}
+
+	for _, rule := range rules {
+		m.evaluateRule(rule, interval)
+	}
+}
+
+// collectRuleSample records a fresh sample of a metric unless a check recorded one recently
+func (m *Monitor) collectRuleSample(metric string, interval time.Duration) {
+	if latest, ok := m.history.Latest(metric); ok && time.Since(latest.Time) < interval/2 {
+		return
+	}
+
+	value, err := ruleMetricSources[metric]()
+	if err != nil {
+		m.logger.Printf("Error sampling %s for rules: %v", metric, err)
+		return
+	}
+	m.history.Record(metric, value, time.Now())
+}
+
+// evaluateRule evaluates a single rule and notifies while it is true
+func (m *Monitor) evaluateRule(rule compiledRule, interval time.Duration) {
+	now := time.Now()
+	result, err := rule.expr.Eval(m.history, now)
+	if err != nil {
+		m.logger.Printf("Error evaluating rule %s: %v", rule.config.Name, err)
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	firing := result != 0
+	state := m.updateCheckState(rule.config.Name, firing)
+	defer m.saveStateIfChanged()
+	if !firing || state.Flapping {
+		return
+	}
+
+	if recentlySent(state, interval) {
+		return
+	}
+
+	level := NotificationLevel(rule.config.Level)
+	if rule.config.Level == "" {
+		level = NotificationLevelWarning
+	}
+
+	text := rule.config.Message
+	if text == "" {
+		text = fmt.Sprintf("Rule %s matched: %s", rule.config.Name, rule.config.Expr)
+	}
+
+	labels := m.alertLabels(rule.config.Name)
+	for key, value := range rule.config.Labels {
+		labels[key] = value
+	}
+
+	hostname, serverIP := GetServerInfo()
+	message := &NotificationMessage{
+		Type:      NotificationTypeSlack, // Will be overridden by providers
+		Level:     level,
+		Title:     fmt.Sprintf("Rule Alert: %s", rule.config.Name),
+		Message:   text,
+		Hostname:  hostname,
+		IP:        serverIP,
+		Timestamp: now,
+		Metric:    rule.config.Name,
+		Value:     m.describeRuleValues(rule),
+		Threshold: rule.config.Expr,
+		AlertID:   state.AlertID,
+		Since:     state.Since,
+		Check:     rule.config.Name,
+		Labels:    labels,
+	}
+
+	m.notify(message, rule.config.EffectiveRateLimits(), nil)
+}
+
+// describeRuleValues lists the current value of every metric a rule references
+func (m *Monitor) describeRuleValues(rule compiledRule) string {
+	values := make([]string, 0, len(rule.metrics))
+	for _, metric := range rule.metrics {
+		if sample, ok := m.history.Latest(metric); ok {
+			values = append(values, fmt.Sprintf("%s=%.2f", metric, sample.Value))
+		}
+	}
+	return strings.Join(values, ", ")
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Contains reports whether t falls inside the time window. Windows whose end
+// is before their start wrap past midnight and belong to the day they start on.
+// A window without start and end covers whole days.
+func (w TimeWindow) Contains(t time.Time) bool {
+	if w.Timezone != "" {
+		loc, err := time.LoadLocation(w.Timezone)
+		if err != nil {
+			return false
+		}
+		t = t.In(loc)
+	}
+
+	day := t.Weekday()
+	inside := true
+
+	if w.Start != "" || w.End != "" {
+		startHour, startMinute, _ := parseClock(w.Start)
+		endHour, endMinute, _ := parseClock(w.End)
+		start := startHour*60 + startMinute
+		end := endHour*60 + endMinute
+		current := t.Hour()*60 + t.Minute()
+
+		switch {
+		case start == end:
+			inside = true
+		case start < end:
+			inside = current >= start && current < end
+		case current >= start:
+			inside = true
+		case current < end:
+			// Early morning part of a window that started the previous day
+			inside = true
+			day = (day + 6) % 7
+		default:
+			inside = false
+		}
+	}
+
+	return inside && w.matchesDay(day)
+}
+
+// matchesDay reports whether the window applies on the given weekday
+func (w TimeWindow) matchesDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, name := range w.Days {
+		if d, err := parseWeekday(name); err == nil && d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the window's times, weekdays and timezone
+func (w TimeWindow) validate() error {
+	if (w.Start == "") != (w.End == "") {
+		return fmt.Errorf("start and end must be set together")
+	}
+	if w.Start != "" {
+		if _, _, err := parseClock(w.Start); err != nil {
+			return fmt.Errorf("start: %w", err)
+		}
+		if _, _, err := parseClock(w.End); err != nil {
+			return fmt.Errorf("end: %w", err)
+		}
+	}
+	for _, day := range w.Days {
+		if _, err := parseWeekday(day); err != nil {
+			return err
+		}
+	}
+	if w.Timezone != "" {
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q", w.Timezone)
+		}
+	}
+	return nil
+}
+
+// ActiveSchedule returns the first schedule override active at t, or nil
+func (mc MonitoringConfig) ActiveSchedule(t time.Time) *ScheduleOverride {
+	for i := range mc.Schedules {
+		if mc.Schedules[i].Contains(t) {
+			return &mc.Schedules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeWindowContains(t *testing.T) {
+	// 2024-05-01 is a Wednesday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 5, day, hour, minute, 0, 0, time.UTC)
+	}
+	business := TimeWindow{Start: "09:00", End: "17:00"}
+	fridayNight := TimeWindow{Days: []string{"friday"}, Start: "22:00", End: "06:00"}
+
+	tests := []struct {
+		name   string
+		window TimeWindow
+		t      time.Time
+		want   bool
+	}{
+		{"empty window", TimeWindow{}, at(1, 3, 0), true},
+		{"inside", business, at(1, 10, 30), true},
+		{"at start", business, at(1, 9, 0), true},
+		{"at end", business, at(1, 17, 0), false},
+		{"before start", business, at(1, 8, 59), false},
+		{"same start and end is whole day", TimeWindow{Start: "00:00", End: "00:00"}, at(1, 23, 59), true},
+		{"matching day", TimeWindow{Days: []string{"Wednesday"}}, at(1, 12, 0), true},
+		{"other day", TimeWindow{Days: []string{"saturday", "sunday"}}, at(1, 12, 0), false},
+		{"wrapping window evening", fridayNight, at(3, 23, 0), true},
+		{"wrapping window next morning", fridayNight, at(4, 3, 0), true},
+		{"wrapping window belongs to previous day", fridayNight, at(3, 3, 0), false},
+		{"wrapping window gap", fridayNight, at(3, 12, 0), false},
+		{"timezone inside", TimeWindow{Start: "09:00", End: "17:00", Timezone: "America/New_York"}, at(1, 14, 0), true},
+		{"timezone outside", TimeWindow{Start: "09:00", End: "17:00", Timezone: "America/New_York"}, at(1, 12, 0), false},
+		{"timezone shifts day", TimeWindow{Days: []string{"tuesday"}, Timezone: "America/New_York"}, at(1, 2, 0), true},
+		{"invalid timezone", TimeWindow{Timezone: "Nowhere/City"}, at(1, 12, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Contains(tt.t); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.t.Format(time.RFC3339), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeWindowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		window  TimeWindow
+		wantErr string
+	}{
+		{name: "valid", window: TimeWindow{Days: []string{"Monday"}, Start: "22:00", End: "06:00", Timezone: "Europe/Berlin"}},
+		{name: "missing end", window: TimeWindow{Start: "09:00"}, wantErr: "start and end must be set together"},
+		{name: "bad start", window: TimeWindow{Start: "9am", End: "17:00"}, wantErr: "start: invalid time"},
+		{name: "bad end", window: TimeWindow{Start: "09:00", End: "25:00"}, wantErr: "end: invalid time"},
+		{name: "bad weekday", window: TimeWindow{Days: []string{"mon"}}, wantErr: "invalid weekday"},
+		{name: "bad timezone", window: TimeWindow{Timezone: "Nowhere/City"}, wantErr: "invalid timezone"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.window.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestActiveSchedule(t *testing.T) {
+	config := MonitoringConfig{Schedules: []ScheduleOverride{
+		{Name: "backups", TimeWindow: TimeWindow{Start: "02:00", End: "04:00"}, Threshold: 98},
+		{Name: "night", TimeWindow: TimeWindow{Start: "22:00", End: "06:00"}, Threshold: 95},
+	}}
+
+	tests := []struct {
+		hour int
+		want string
+	}{
+		{hour: 3, want: "backups"},
+		{hour: 23, want: "night"},
+		{hour: 12},
+	}
+	for _, tt := range tests {
+		got := config.ActiveSchedule(time.Date(2024, 5, 1, tt.hour, 0, 0, 0, time.UTC))
+		name := ""
+		if got != nil {
+			name = got.Name
+		}
+		if name != tt.want {
+			t.Errorf("ActiveSchedule(%02d:00) = %q, want %q", tt.hour, name, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+const (
+	stateFileName    = "state.json"
+	stateFileVersion = 4
+)
+
+// persistedState is the alert state saved between daemon restarts
+type persistedState struct {
+	Version          int                              `json:"version"`
+	SavedAt          time.Time                        `json:"saved_at"`
+	Checks           map[string]*checkState           `json:"checks"`
+	Limiters         map[string]*SlidingWindowLimiter `json:"limiters"`
+	ProviderLimiters map[string]*SlidingWindowLimiter `json:"provider_limiters"`
+	Baselines        map[string]*Baseline             `json:"baselines,omitempty"`
+	Escalations      escalationState                  `json:"escalations,omitzero"`
+}
+
+// getDataDir returns the default directory for persistent state
+func getDataDir() string {
+	if os.Geteuid() == 0 {
+		return filepath.Join("/var/lib", appName)
+	}
+
+	// Use XDG_DATA_HOME if available
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, appName)
+	}
+
+	return filepath.Join(os.Getenv("HOME"), ".local", "share", appName)
+}
+
+// loadState restores alert state and counters from the state file.
+// A missing state file is not an error.
+func (m *Monitor) loadState() error {
+	data, err := os.ReadFile(m.statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state persistedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to parse state file: %w", err)
+	}
+
+	// Version 1 files kept calendar-day counters, which are ignored
+	if state.Version < 1 || state.Version > stateFileVersion {
+		return fmt.Errorf("unsupported state file version %d", state.Version)
+	}
+
+	// Drop state of checks removed from the configuration, so a check that was
+	// firing when it was removed doesn't stay firing forever
+	names := m.config.checkNames()
+	maps.DeleteFunc(state.Checks, func(check string, _ *checkState) bool { return !names[check] })
+	maps.DeleteFunc(state.Limiters, func(check string, _ *SlidingWindowLimiter) bool { return !names[check] })
+	maps.DeleteFunc(state.Baselines, func(metric string, _ *Baseline) bool { return !names[metric] })
+
+	// Only alerts that are still firing keep escalating or stay acknowledged
+	firing := make(map[string]bool)
+	for _, check := range state.Checks {
+		if check.Firing && check.AlertID != "" {
+			firing[check.AlertID] = true
+		}
+	}
+	state.Escalations.Pending = slices.DeleteFunc(state.Escalations.Pending, func(saved savedEscalation) bool {
+		return saved.Message == nil || !firing[saved.Message.AlertID]
+	})
+	state.Escalations.Acknowledged = slices.DeleteFunc(state.Escalations.Acknowledged, func(alertID string) bool {
+		return !firing[alertID]
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if state.Checks != nil {
+		m.checkStates = state.Checks
+	}
+	if state.Limiters != nil {
+		m.limiters = state.Limiters
+	}
+	if state.Baselines != nil {
+		m.baselines = state.Baselines
+	}
+	m.notificationManager.RestoreLimiters(state.ProviderLimiters)
+	m.escalator.Restore(m.ctx, state.Escalations)
+
+	m.logger.Printf("Restored alert state from %s (saved %s)", m.statePath, state.SavedAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// saveStateIfChanged saves the state file when alert state or counters changed
+// since it was last written. The caller must hold m.mu.
+func (m *Monitor) saveStateIfChanged() {
+	if m.stateChanged {
+		m.saveState()
+	}
+}
+
+// saveState writes alert state and counters to the state file, logging any failure.
+// The caller must hold m.mu.
+func (m *Monitor) saveState() {
+	state := persistedState{
+		Version:          stateFileVersion,
+		SavedAt:          time.Now(),
+		Checks:           m.checkStates,
+		Limiters:         m.limiters,
+		ProviderLimiters: m.notificationManager.SnapshotLimiters(),
+		Baselines:        m.baselines,
+		Escalations:      m.escalator.Snapshot(),
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		m.logger.Printf("Failed to encode state: %v", err)
+		return
+	}
+
+	if err := writeFileAtomic(m.statePath, data, 0o600); err != nil {
+		m.logger.Printf("Failed to save state: %v", err)
+		return
+	}
+	m.stateChanged = false
+	m.stateSavedAt = state.SavedAt
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over path,
+// so readers never see a partially written file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to replace file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	statePath := filepath.Join(t.TempDir(), stateFileName)
+
+	config := &Config{
+		Disk:   MonitoringConfig{Enabled: true},
+		CPU:    MonitoringConfig{Enabled: true},
+		Memory: MonitoringConfig{Enabled: true},
+	}
+
+	saved := newTestMonitor(t, &recordingProvider{})
+	saved.config = config
+	saved.statePath = statePath
+	saved.checkStates["disk"] = &checkState{
+		Firing:      true,
+		AlertID:     "disk-1",
+		Since:       now.Add(-time.Hour),
+		Transitions: []time.Time{now.Add(-time.Hour)},
+		Last: &NotificationMessage{
+			Title:     "Disk usage high",
+			Level:     NotificationLevelError,
+			Check:     "disk",
+			AlertID:   "disk-1",
+			Labels:    map[string]string{"metric": "disk"},
+			Timestamp: now.Add(-time.Hour),
+		},
+		LastSent: now.Add(-time.Hour),
+	}
+	saved.checkStates["cpu"] = &checkState{Unknown: true}
+	saved.limiters["disk"] = &SlidingWindowLimiter{Sent: []time.Time{now.Add(-time.Hour)}, Notified: true}
+	saved.baselines["memory"] = &Baseline{Mean: 42.5, Variance: 3, Samples: 100, Updated: now}
+	providerLimiters := map[string]*SlidingWindowLimiter{"test": {Sent: []time.Time{now.Add(-time.Minute)}}}
+	saved.notificationManager.RestoreLimiters(providerLimiters)
+
+	saved.mu.Lock()
+	saved.saveState()
+	saved.mu.Unlock()
+
+	info, err := os.Stat(statePath)
+	if err != nil {
+		t.Fatalf("state file not written: %v", err)
+	}
+	if mode := info.Mode().Perm(); mode != 0o600 {
+		t.Errorf("state file mode = %o, want 600", mode)
+	}
+
+	loaded := newTestMonitor(t, &recordingProvider{})
+	loaded.config = config
+	loaded.statePath = statePath
+	if err := loaded.loadState(); err != nil {
+		t.Fatalf("loadState() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(loaded.checkStates, saved.checkStates) {
+		t.Errorf("checkStates = %+v, want %+v", loaded.checkStates, saved.checkStates)
+	}
+	if !reflect.DeepEqual(loaded.limiters, saved.limiters) {
+		t.Errorf("limiters = %+v, want %+v", loaded.limiters, saved.limiters)
+	}
+	if !reflect.DeepEqual(loaded.baselines, saved.baselines) {
+		t.Errorf("baselines = %+v, want %+v", loaded.baselines, saved.baselines)
+	}
+	if got := loaded.notificationManager.SnapshotLimiters(); !reflect.DeepEqual(got, providerLimiters) {
+		t.Errorf("provider limiters = %+v, want %+v", got, providerLimiters)
+	}
+}
+
+func TestStateRestoresEscalations(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), stateFileName)
+	config := &Config{
+		Disk: MonitoringConfig{Enabled: true},
+		CPU:  MonitoringConfig{Enabled: true},
+	}
+	policies := []EscalationPolicy{{
+		Name:  "oncall",
+		Tiers: []EscalationTier{{AfterMinutes: 15, Providers: []string{"test"}}, {AfterMinutes: 30, Providers: []string{"test"}}},
+	}}
+	alert := func(check, alertID string) *NotificationMessage {
+		return &NotificationMessage{Level: NotificationLevelError, Title: check + " alert", Check: check, AlertID: alertID}
+	}
+
+	saved := newTestMonitor(t, &recordingProvider{})
+	saved.config = config
+	saved.statePath = statePath
+	saved.escalator = NewEscalator(policies, saved.notificationManager, saved.logger)
+	saved.checkStates["disk"] = &checkState{Firing: true, AlertID: "d1"}
+	saved.checkStates["cpu"] = &checkState{Firing: true, AlertID: "c1"}
+	saved.escalator.Track(t.Context(), alert("disk", "d1"))
+	saved.escalator.Track(t.Context(), alert("cpu", "c1"))
+	saved.escalator.Track(t.Context(), alert("memory", "m1")) // resolved while the daemon was down
+	if err := saved.escalator.Acknowledge("c1"); err != nil {
+		t.Fatal(err)
+	}
+	// The first tier fell due while the daemon was stopped
+	saved.escalator.pending["d1"].due = time.Now().Add(-time.Minute)
+	saved.escalator.Stop()
+	saved.mu.Lock()
+	saved.saveState()
+	saved.mu.Unlock()
+
+	provider := &recordingProvider{}
+	loaded := newTestMonitor(t, provider)
+	loaded.config = config
+	loaded.statePath = statePath
+	loaded.escalator = NewEscalator(policies, loaded.notificationManager, loaded.logger)
+	defer loaded.escalator.Stop()
+	if err := loaded.loadState(); err != nil {
+		t.Fatalf("loadState() error = %v", err)
+	}
+
+	if _, ok := loaded.escalator.pending["m1"]; ok {
+		t.Error("restored the escalation of an alert that is no longer firing")
+	}
+	if !loaded.escalator.acknowledged["c1"] {
+		t.Error("acknowledgement of c1 was not restored")
+	}
+	if err := loaded.escalator.Acknowledge("c1"); err == nil {
+		t.Error("acknowledged alert c1 is escalating again")
+	}
+
+	// The overdue tier is notified right away and the next one scheduled
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		provider.mu.Lock()
+		sent := len(provider.sent)
+		provider.mu.Unlock()
+		if sent > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("overdue escalation of d1 was not sent after the restart")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	provider.mu.Lock()
+	title := provider.sent[0].Title
+	provider.mu.Unlock()
+	if title != "[Escalated] disk alert" {
+		t.Errorf("escalation title = %q", title)
+	}
+
+	loaded.escalator.mu.Lock()
+	pending := loaded.escalator.pending["d1"]
+	next, due := pending.next, pending.due
+	loaded.escalator.mu.Unlock()
+	if next != 1 || time.Until(due) < 14*time.Minute {
+		t.Errorf("after restart next = %d, due in %s; want tier 2 due in 15m", next, time.Until(due))
+	}
+}
+
+func TestLoadState(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // empty means no state file
+		wantErr string
+	}{
+		{name: "missing file"},
+		{name: "empty state", content: `{"version": 4}`},
+		{name: "before escalations", content: `{"version": 3, "checks": {"cpu": {"firing": true}}}`},
+		{name: "older version", content: `{"version": 1, "checks": {"cpu": {"firing": true}}}`},
+		{name: "unsupported version", content: `{"version": 99}`, wantErr: "unsupported state file version 99"},
+		{name: "no version", content: `{}`, wantErr: "unsupported state file version 0"},
+		{name: "corrupt", content: `{"version":`, wantErr: "failed to parse state file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMonitor(t, &recordingProvider{})
+			m.statePath = filepath.Join(t.TempDir(), stateFileName)
+			if tt.content != "" {
+				if err := os.WriteFile(m.statePath, []byte(tt.content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := m.loadState()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("loadState() error = %v", err)
+				}
+				if m.checkStates == nil || m.limiters == nil || m.baselines == nil {
+					t.Error("loadState() left nil state maps")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadState() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadStateDropsRemovedChecks(t *testing.T) {
+	statePath := filepath.Join(t.TempDir(), stateFileName)
+
+	saved := newTestMonitor(t, &recordingProvider{})
+	saved.config = &Config{
+		Disk:  MonitoringConfig{Enabled: true},
+		Rules: []RuleConfig{{Name: "busy", Expr: "cpu.usage > 90"}},
+	}
+	saved.statePath = statePath
+	saved.checkStates["disk"] = &checkState{}
+	saved.checkStates["busy"] = &checkState{Firing: true, AlertID: "b1"}
+	saved.limiters["busy"] = &SlidingWindowLimiter{}
+	saved.baselines["disk"] = &Baseline{Samples: 1}
+	saved.mu.Lock()
+	saved.saveState()
+	saved.mu.Unlock()
+
+	// The rule is removed and disk monitoring disabled before the restart
+	loaded := newTestMonitor(t, &recordingProvider{})
+	loaded.config = &Config{CPU: MonitoringConfig{Enabled: true}}
+	loaded.statePath = statePath
+	if err := loaded.loadState(); err != nil {
+		t.Fatalf("loadState() error = %v", err)
+	}
+
+	if len(loaded.checkStates) != 0 || len(loaded.limiters) != 0 || len(loaded.baselines) != 0 {
+		t.Errorf("kept state of removed checks: checks %v, limiters %v, baselines %v",
+			loaded.checkStates, loaded.limiters, loaded.baselines)
+	}
+}
+
+func TestSaveStateOnlyWhenChanged(t *testing.T) {
+	m := newTestMonitor(t, &recordingProvider{})
+	m.statePath = filepath.Join(t.TempDir(), stateFileName)
+
+	steps := []struct {
+		name      string
+		firing    bool
+		wantWrite bool
+	}{
+		{name: "healthy check", firing: false, wantWrite: false},
+		{name: "starts firing", firing: true, wantWrite: true},
+		{name: "still firing", firing: true, wantWrite: false},
+		{name: "recovers", firing: false, wantWrite: true},
+		{name: "still healthy", firing: false, wantWrite: false},
+	}
+	for _, step := range steps {
+		os.Remove(m.statePath)
+
+		m.mu.Lock()
+		m.updateCheckState("disk", step.firing)
+		m.saveStateIfChanged()
+		m.mu.Unlock()
+
+		_, err := os.Stat(m.statePath)
+		if written := err == nil; written != step.wantWrite {
+			t.Errorf("%s: state written = %v, want %v", step.name, written, step.wantWrite)
+		}
+	}
+}
@@ -8,10 +8,16 @@ import (
 	"syscall"
 )
 
-// setPlatformSysProcAttr sets platform-specific process attributes for Unix systems
-// nolint:unused // This function is kept for potential future background process functionality
+// setPlatformSysProcAttr sets platform-specific process attributes for Unix systems,
+// starting the command in its own process group
 func setPlatformSysProcAttr(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
 	}
 }
+
+// killProcessGroup kills a command started with setPlatformSysProcAttr together
+// with any children it spawned
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
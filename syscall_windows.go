@@ -8,11 +8,16 @@ import (
 	"syscall"
 )
 
-// setPlatformSysProcAttr sets platform-specific process attributes for Windows systems
-// nolint:unused // This function is kept for potential future background process functionality
+// setPlatformSysProcAttr sets platform-specific process attributes for Windows systems,
+// starting the command in a new process group
 func setPlatformSysProcAttr(cmd *exec.Cmd) {
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		HideWindow:    true,
 		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP,
 	}
 }
+
+// killProcessGroup kills a command started with setPlatformSysProcAttr
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -59,6 +59,33 @@ func GetMemoryUsage() (float64, error) {
 	return getUnixMemoryUsage()
 }
 
+// GetSwapUsage returns swap usage percentage using native Go
+func GetSwapUsage() (float64, error) {
+	if runtime.GOOS == "windows" {
+		return getWindowsSwapUsage()
+	}
+	return getUnixSwapUsage()
+}
+
+// MountUsage represents the usage of a mounted filesystem
+type MountUsage struct {
+	Mount       string
+	UsedPercent float64
+}
+
+// GetMountUsages returns usage percentages for all local mounted filesystems.
+// It is implemented for Linux, macOS and the system drive on Windows.
+func GetMountUsages() ([]MountUsage, error) {
+	if runtime.GOOS == "windows" {
+		usage, err := getWindowsDiskUsage()
+		if err != nil {
+			return nil, err
+		}
+		return []MountUsage{{Mount: "C:", UsedPercent: float64(usage)}}, nil
+	}
+	return getUnixMountUsages()
+}
+
 // getUnixDiskUsage reads /proc/mounts and /proc/stat for disk usage
 func getUnixDiskUsage() (int, error) {
 	// Read /proc/mounts to find root filesystem
@@ -188,6 +215,47 @@ func getUnixMemoryUsage() (float64, error) {
 	return usagePercent, nil
 }
 
+// getUnixSwapUsage reads /proc/meminfo for swap usage
+func getUnixSwapUsage() (float64, error) {
+	file, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, fmt.Errorf("failed to open /proc/meminfo: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	var total, free uint64
+	found := false
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		val, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "SwapTotal:":
+			total = val
+			found = true
+		case "SwapFree:":
+			free = val
+		}
+	}
+
+	if !found {
+		return 0, fmt.Errorf("could not read swap information")
+	}
+
+	// A host without swap reports no usage
+	if total == 0 {
+		return 0, nil
+	}
+
+	return (float64(total-free) / float64(total)) * 100.0, nil
+}
+
 // Windows implementations using WMI via PowerShell
 func getWindowsDiskUsage() (int, error) {
 	// This is a simplified implementation using PowerShell
@@ -248,3 +316,20 @@ func getWindowsMemoryUsage() (float64, error) {
 
 	return usage, nil
 }
+
+func getWindowsSwapUsage() (float64, error) {
+	cmd := exec.Command("powershell", "-Command",
+		"$p = Get-WmiObject -Class Win32_PageFileUsage | Measure-Object -Property AllocatedBaseSize,CurrentUsage -Sum; if ($p[0].Sum -gt 0) { [math]::Round(($p[1].Sum / $p[0].Sum) * 100, 2) } else { 0 }")
+
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get Windows swap usage: %w", err)
+	}
+
+	usage, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse Windows swap usage: %w", err)
+	}
+
+	return usage, nil
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateFuncs are the helper functions available to notification templates
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper":            strings.ToUpper,
+	"lower":            strings.ToLower,
+	"join":             strings.Join,
+	"emoji":            levelEmoji,
+	"humanizeBytes":    humanizeBytes,
+	"humanizeDuration": humanizeDuration,
+	"since":            time.Since,
+}
+
+// TemplateConfig holds the optional title and body templates of a notification,
+// each given inline or as a file
+type TemplateConfig struct {
+	Title     string `mapstructure:"title" yaml:"title,omitempty"`
+	Body      string `mapstructure:"body" yaml:"body,omitempty"`
+	TitleFile string `mapstructure:"title_file" yaml:"title_file,omitempty"`
+	BodyFile  string `mapstructure:"body_file" yaml:"body_file,omitempty"`
+}
+
+// IsZero reports whether no template is configured
+func (t TemplateConfig) IsZero() bool {
+	return t == TemplateConfig{}
+}
+
+// Load reads and parses the configured templates. It returns nil when no
+// template is configured.
+func (t TemplateConfig) Load() (*MessageTemplate, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+
+	title, err := loadTemplate("title", t.Title, t.TitleFile)
+	if err != nil {
+		return nil, err
+	}
+	body, err := loadTemplate("body", t.Body, t.BodyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MessageTemplate{title: title, body: body}, nil
+}
+
+// loadTemplate parses an inline or file template, returning nil if neither is set
+func loadTemplate(name, inline, file string) (*template.Template, error) {
+	if inline != "" && file != "" {
+		return nil, fmt.Errorf("template %s and %s_file are mutually exclusive", name, name)
+	}
+
+	text := inline
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s template: %w", name, err)
+		}
+		text = string(data)
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// MessageTemplate renders the title and body of a notification
+type MessageTemplate struct {
+	title *template.Template
+	body  *template.Template
+}
+
+// Render returns the title and body of a message. Parts without a template, or
+// whose template fails, use the given default layouts; a failure is noted in
+// the body so a broken template never loses an alert.
+func (mt *MessageTemplate) Render(message *NotificationMessage, defaultTitle, defaultBody func(*NotificationMessage) string) (string, string) {
+	if mt == nil {
+		return defaultTitle(message), defaultBody(message)
+	}
+
+	title, titleErr := mt.execute(mt.title, message, defaultTitle)
+	body, bodyErr := mt.execute(mt.body, message, defaultBody)
+
+	for _, err := range []error{titleErr, bodyErr} {
+		if err != nil {
+			body += fmt.Sprintf("\n\n(template error: %v)", err)
+		}
+	}
+	return title, body
+}
+
+// HasBody reports whether a body template is configured
+func (mt *MessageTemplate) HasBody() bool {
+	return mt != nil && mt.body != nil
+}
+
+// execute renders a template, falling back to the default layout when the
+// template is missing or fails
+func (mt *MessageTemplate) execute(tmpl *template.Template, message *NotificationMessage, fallback func(*NotificationMessage) string) (string, error) {
+	if tmpl == nil {
+		return fallback(message), nil
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, message); err != nil {
+		return fallback(message), err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+// alertTitle is the default title layout of providers that show the title as is
+func alertTitle(message *NotificationMessage) string {
+	return message.Title
+}
+
+// alertText is the default body layout of providers that show the message text
+// next to their own fields
+func alertText(message *NotificationMessage) string {
+	return message.Message
+}
+
+// emojiTitle is the default title layout of providers that prefix the level emoji
+func emojiTitle(message *NotificationMessage) string {
+	return fmt.Sprintf("%s %s", levelEmoji(message.Level), message.Title)
+}
+
+// hostTitle is the default title layout of push providers, which show the host
+// in the title
+func hostTitle(message *NotificationMessage) string {
+	return fmt.Sprintf("%s - %s", message.Title, message.Hostname)
+}
+
+// humanizeBytes formats a byte count with binary units, e.g. 1.5 GiB
+func humanizeBytes(v interface{}) (string, error) {
+	n, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	i := 0
+	for math.Abs(n) >= 1024 && i < len(units)-1 {
+		n /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", n, units[i]), nil
+	}
+	return fmt.Sprintf("%.1f %s", n, units[i]), nil
+}
+
+// humanizeDuration formats a duration, or a number of seconds, with its two
+// largest units, e.g. 2h 5m or 3d 4h
+func humanizeDuration(v interface{}) (string, error) {
+	var d time.Duration
+	switch value := v.(type) {
+	case time.Duration:
+		d = value
+	case string:
+		if parsed, err := time.ParseDuration(value); err == nil {
+			d = parsed
+			break
+		}
+		seconds, err := toFloat(value)
+		if err != nil {
+			return "", err
+		}
+		d = time.Duration(seconds * float64(time.Second))
+	default:
+		seconds, err := toFloat(value)
+		if err != nil {
+			return "", err
+		}
+		d = time.Duration(seconds * float64(time.Second))
+	}
+
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	if d < time.Second {
+		return sign + d.Round(time.Millisecond).String(), nil
+	}
+
+	parts := []struct {
+		unit string
+		size time.Duration
+	}{
+		{"d", 24 * time.Hour}, {"h", time.Hour}, {"m", time.Minute}, {"s", time.Second},
+	}
+	var out []string
+	for _, part := range parts {
+		if d >= part.size || len(out) > 0 {
+			out = append(out, fmt.Sprintf("%d%s", d/part.size, part.unit))
+			d %= part.size
+		}
+		if len(out) == 2 {
+			break
+		}
+	}
+	if len(out) == 2 && strings.HasPrefix(out[1], "0") {
+		out = out[:1]
+	}
+	return sign + strings.Join(out, " "), nil
+}
+
+// toFloat converts a template argument to a number. Strings may carry a unit
+// suffix such as "%", as in NotificationMessage values.
+func toFloat(v interface{}) (float64, error) {
+	switch value := v.(type) {
+	case int:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case uint64:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimRight(value, "% ")), 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not a number", value)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("unsupported value %v of type %T", v, v)
+	}
+}
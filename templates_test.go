@@ -0,0 +1,381 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testMessage() *NotificationMessage {
+	return &NotificationMessage{
+		Level:     NotificationLevelError,
+		Title:     "Disk usage critical",
+		Message:   "Disk usage is above the threshold",
+		Hostname:  "web-1",
+		IP:        "10.0.0.1",
+		Metric:    "disk",
+		Value:     "93.12%",
+		Threshold: "90%",
+		AlertID:   "a1",
+		Check:     "disk",
+		Labels:    map[string]string{"env": "prod"},
+		Timestamp: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestHumanizeBytes(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{int64(1610612736), "1.5 GiB"},
+		{uint64(1 << 40), "1.0 TiB"},
+		{"2048", "2.0 KiB"},
+	}
+	for _, tt := range tests {
+		got, err := humanizeBytes(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("humanizeBytes(%v) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	if _, err := humanizeBytes("full"); err == nil {
+		t.Error("humanizeBytes accepted a non-number")
+	}
+}
+
+func TestHumanizeDuration(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{7500, "2h 5m"},
+		{3600, "1h"},
+		{59.5, "59s"},
+		{90 * time.Second, "1m 30s"},
+		{"26h", "1d 2h"},
+		{"45", "45s"},
+		{250 * time.Millisecond, "250ms"},
+		{-90 * time.Second, "-1m 30s"},
+	}
+	for _, tt := range tests {
+		got, err := humanizeDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("humanizeDuration(%v) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestTemplateConfigLoad(t *testing.T) {
+	dir := t.TempDir()
+	bodyFile := filepath.Join(dir, "body.tmpl")
+	if err := os.WriteFile(bodyFile, []byte("{{ .Hostname }}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  TemplateConfig
+		wantNil bool
+		wantErr string
+	}{
+		{name: "empty", config: TemplateConfig{}, wantNil: true},
+		{name: "inline", config: TemplateConfig{Title: "{{ .Title }}"}},
+		{name: "file", config: TemplateConfig{BodyFile: bodyFile}},
+		{name: "both", config: TemplateConfig{Title: "x", TitleFile: bodyFile}, wantErr: "mutually exclusive"},
+		{name: "syntax", config: TemplateConfig{Body: "{{ .Title"}, wantErr: "invalid body template"},
+		{name: "unknown function", config: TemplateConfig{Body: "{{ nope .Title }}"}, wantErr: "invalid body template"},
+		{name: "missing file", config: TemplateConfig{BodyFile: filepath.Join(dir, "missing")}, wantErr: "failed to read body template"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := tt.config.Load()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if (tmpl == nil) != tt.wantNil {
+				t.Fatalf("Load() = %v, want nil %v", tmpl, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestMessageTemplateRender(t *testing.T) {
+	message := testMessage()
+	message.Since = time.Now().Add(-2 * time.Hour)
+
+	tests := []struct {
+		name      string
+		config    TemplateConfig
+		wantTitle string
+		wantBody  string
+	}{
+		{
+			name:      "defaults",
+			wantTitle: "❌ Disk usage critical",
+			wantBody:  "Disk usage is above the threshold",
+		},
+		{
+			name: "fields, labels and helpers",
+			config: TemplateConfig{
+				Title: "{{ emoji .Level }} [{{ upper .Labels.env }}] {{ .Title }}",
+				Body:  "{{ .Value }} of {{ humanizeBytes 1610612736 }} for {{ humanizeDuration (since .Since) }}\n",
+			},
+			wantTitle: "❌ [PROD] Disk usage critical",
+			wantBody:  "93.12% of 1.5 GiB for 2h",
+		},
+		{
+			name: "documented example",
+			config: TemplateConfig{
+				Body: "{{ .Metric }} at {{ .Value }} (threshold {{ .Threshold }})\n" +
+					"{{- if not .Since.IsZero }}, firing for {{ humanizeDuration (since .Since) }}{{ end }}\n",
+			},
+			wantTitle: "❌ Disk usage critical",
+			wantBody:  "disk at 93.12% (threshold 90%), firing for 2h",
+		},
+		{
+			name:      "missing label",
+			config:    TemplateConfig{Body: "team={{ .Labels.team }}"},
+			wantTitle: "❌ Disk usage critical",
+			wantBody:  "team=",
+		},
+		{
+			name:      "runtime error falls back",
+			config:    TemplateConfig{Title: "{{ humanizeBytes .Message }}"},
+			wantTitle: "❌ Disk usage critical",
+			wantBody:  "Disk usage is above the threshold\n\n(template error: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := tt.config.Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			title, body := tmpl.Render(message, emojiTitle, alertText)
+			if title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", title, tt.wantTitle)
+			}
+			if !strings.HasPrefix(body, tt.wantBody) {
+				t.Errorf("body = %q, want prefix %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+// captureServer returns a server that records the body of the last request
+func captureServer(t *testing.T) (*httptest.Server, *[]byte) {
+	t.Helper()
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &body
+}
+
+func TestProvidersUseTemplates(t *testing.T) {
+	templated := TemplateConfig{Title: "T {{ .Labels.env }}", Body: "B {{ .Value }}"}
+
+	tests := []struct {
+		name      string
+		provider  func(url string, tmpl TemplateConfig) NotificationProvider
+		wantPlain []string
+		wantTmpl  []string
+	}{
+		{
+			name: "ntfy",
+			provider: func(url string, tmpl TemplateConfig) NotificationProvider {
+				return NewNtfyProvider(url+"/alerts", "", tmpl, http.DefaultClient)
+			},
+			wantPlain: []string{`"title":"Disk usage critical - web-1"`, `"message":"Disk usage is above the threshold\n\nMetric: disk`},
+			wantTmpl:  []string{`"title":"T prod"`, `"message":"B 93.12%"`},
+		},
+		{
+			name: "gotify",
+			provider: func(url string, tmpl TemplateConfig) NotificationProvider {
+				return NewGotifyProvider(url, "token", tmpl, http.DefaultClient)
+			},
+			wantPlain: []string{`"title":"❌ Disk usage critical - web-1"`},
+			wantTmpl:  []string{`"title":"T prod"`, `"message":"B 93.12%"`},
+		},
+		{
+			name: "pushover",
+			provider: func(url string, tmpl TemplateConfig) NotificationProvider {
+				return NewPushoverProvider("token", "user", 0, 0, url, tmpl, http.DefaultClient)
+			},
+			wantPlain: []string{"title=Disk+usage+critical+-+web-1"},
+			wantTmpl:  []string{"title=T+prod", "message=B+93.12%25"},
+		},
+		{
+			name: "matrix",
+			provider: func(url string, tmpl TemplateConfig) NotificationProvider {
+				return NewMatrixProvider(url, "token", "!room:example.org", tmpl, http.DefaultClient)
+			},
+			wantPlain: []string{`"body":"❌ Disk usage critical\nDisk usage is above the threshold`, `\u003cli\u003e`},
+			wantTmpl:  []string{`"body":"T prod\nB 93.12%"`, `\u003cp\u003eB 93.12%\u003c/p\u003e`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, tc := range []struct {
+				tmpl TemplateConfig
+				want []string
+			}{{TemplateConfig{}, tt.wantPlain}, {templated, tt.wantTmpl}} {
+				server, body := captureServer(t)
+				provider := tt.provider(server.URL, tc.tmpl)
+				if err := provider.Validate(); err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				if err := provider.Send(t.Context(), testMessage()); err != nil {
+					t.Fatalf("Send() error = %v", err)
+				}
+				for _, want := range tc.want {
+					if !strings.Contains(string(*body), want) {
+						t.Errorf("request %s does not contain %s", *body, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSyslogAndJournaldUseTemplates(t *testing.T) {
+	templated := TemplateConfig{Title: "T", Body: "{{ .Check }}\non {{ .Hostname }}"}
+
+	syslog := NewSyslogProvider("udp", "127.0.0.1:514", "", "", templated)
+	if err := syslog.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if got := syslog.format(testMessage()); !strings.HasSuffix(got, "\ufeffT: disk on web-1") {
+		t.Errorf("syslog message = %q", got)
+	}
+
+	// Validate needs a running journald, so load the template directly
+	journald := NewJournaldProvider("", nil, templated)
+	tmpl, err := journald.Template.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	journald.template = tmpl
+	if entry := string(journald.entry(testMessage())); !strings.HasPrefix(entry, "MESSAGE\n") ||
+		!strings.Contains(entry, "T: disk\non web-1\n") {
+		t.Errorf("journal entry = %q", entry)
+	}
+}
+
+func TestConfigRejectsTemplatesForStructuredProviders(t *testing.T) {
+	config := &Config{}
+	err := config.validateNotification(&NotificationConfig{
+		Type:       "pagerduty",
+		RoutingKey: "key",
+		Template:   TemplateConfig{Title: "x"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "not supported for pagerduty") {
+		t.Fatalf("validateNotification() error = %v", err)
+	}
+
+	err = config.validateNotification(&NotificationConfig{
+		Type:     "ntfy",
+		URL:      "https://ntfy.sh/alerts",
+		Template: TemplateConfig{Body: "{{ .Nope"},
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "template: ") {
+		t.Fatalf("validateNotification() error = %v", err)
+	}
+}
+
+// Ensure payloads stay valid JSON when templates contain quotes
+func TestTemplatedPayloadIsValidJSON(t *testing.T) {
+	server, body := captureServer(t)
+	provider := NewDiscordProvider("https://discord.com/api/webhooks/x", TemplateConfig{Body: `say "{{ .Title }}"`}, http.DefaultClient)
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	provider.WebhookURL = server.URL
+	if err := provider.Send(t.Context(), testMessage()); err != nil {
+		t.Fatal(err)
+	}
+
+	var payload struct {
+		Embeds []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+		} `json:"embeds"`
+	}
+	if err := json.Unmarshal(*body, &payload); err != nil {
+		t.Fatalf("invalid JSON %s: %v", *body, err)
+	}
+	if got := payload.Embeds[0].Description; got != `say "Disk usage critical"` {
+		t.Errorf("description = %q", got)
+	}
+	if got := payload.Embeds[0].Title; got != "Disk usage critical" {
+		t.Errorf("title = %q", got)
+	}
+}
+
+// A label that only some alerts carry must not fail the webhook payload
+func TestWebhookPayloadMissingLabel(t *testing.T) {
+	server, body := captureServer(t)
+	provider := NewWebhookProvider(server.URL, "", nil, `{"team": "{{ .Labels.team }}", "env": "{{ .Labels.env }}"}`, http.DefaultClient)
+	if err := provider.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := provider.Send(t.Context(), testMessage()); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if got, want := string(*body), `{"team": "", "env": "prod"}`; got != want {
+		t.Errorf("payload = %s, want %s", got, want)
+	}
+}
+
+func TestEmailUsesTemplates(t *testing.T) {
+	tests := []struct {
+		name   string
+		config TemplateConfig
+		want   []string
+	}{
+		{
+			name: "defaults",
+			want: []string{"Subject: [ERROR] Disk usage critical - web-1", "<table"},
+		},
+		{
+			name:   "templated",
+			config: TemplateConfig{Title: "Disk on\n{{ .Hostname }}", Body: "Used {{ .Value }} <b>"},
+			want:   []string{"Subject: Disk on web-1", "Used 93.12% <b>", "Used 93.12% &lt;b&gt;"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := NewEmailProvider("smtp.example.com", 0, "alerts@example.com", []string{"ops@example.com"},
+				"", "", "", "", "", tt.config)
+			if err := provider.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			raw, err := provider.buildMessage(testMessage())
+			if err != nil {
+				t.Fatalf("buildMessage() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(raw), want) {
+					t.Errorf("email does not contain %q:\n%s", want, raw)
+				}
+			}
+		})
+	}
+}